@@ -26,9 +26,21 @@ func DebugEmailRenderTemplates() {
 			"EMAIL_VERIFY.txt": tools.LocalsEmailVerify{
 				Token: exampleToken,
 			},
+			"EMAIL_VERIFY_CHANGE.txt": tools.LocalsEmailVerifyChange{
+				Token:    exampleToken,
+				Lifetime: fmt.Sprint(tools.TOKEN_LIFETIME_EMAIL_CHANGE.Hours()),
+			},
 			"LOGIN_FORGOT_PASSWORD.txt": tools.LocalsLoginForgotPassword{
 				Token: exampleToken,
 			},
+			"LOGIN_EMAIL_LINK.txt": tools.LocalsLoginEmailLink{
+				Token:          exampleToken,
+				Lifetime:       fmt.Sprint(tools.TOKEN_LIFETIME_EMAIL_LINK.Minutes()),
+				Timestamp:      exampleTime,
+				IpAddress:      exampleAddress,
+				DeviceBrowser:  exampleBrowser,
+				DeviceLocation: exampleLocation,
+			},
 			"LOGIN_NEW_DEVICE.txt": tools.LocalsLoginNewDevice{
 				Timestamp:      exampleTime,
 				IpAddress:      exampleAddress,
@@ -47,10 +59,26 @@ func DebugEmailRenderTemplates() {
 				Lifetime: fmt.Sprint(tools.TOKEN_LIFETIME_EMAIL_PASSCODE.Minutes()),
 			},
 			"NOTIFY_USER_DELETED.txt": tools.LocalsNotifyUserDeleted{
-				Reason: "User Request",
+				Content: "account",
+				Reason:  "User Request",
+			},
+			"NOTIFY_USER_DATA_EXPORT.txt": tools.LocalsNotifyUserDataExport{
+				Token:    exampleToken,
+				Lifetime: fmt.Sprint(tools.TOKEN_LIFETIME_EXPORT.Hours()),
+			},
+			"NOTIFY_USER_DELETION_PENDING.txt": tools.LocalsNotifyUserDeletionPending{
+				Token:    exampleToken,
+				Lifetime: fmt.Sprint(tools.DELETE_GRACE_DAYS),
+			},
+			"NOTIFY_USER_SUSPENDED.txt": tools.LocalsNotifyUserSuspended{
+				Reason: "Spam",
+			},
+			"NOTIFY_USER_EMAIL_MODIFIED.txt": tools.LocalsNotifyUserEmailModified{
+				Email:    "user@example.com",
+				Token:    exampleToken,
+				Lifetime: fmt.Sprint(tools.TOKEN_LIFETIME_EMAIL_REVERT.Hours() / 24),
 			},
-			"NOTIFY_USER_EMAIL_MODIFIED.txt": tools.LocalsNotifyUserEmailModified{},
-			"NOTIFY_USER_PASS_MODIFIED.txt":  tools.LocalsNotifyUserPasswordModified{},
+			"NOTIFY_USER_PASS_MODIFIED.txt": tools.LocalsNotifyUserPasswordModified{},
 		}
 	)
 
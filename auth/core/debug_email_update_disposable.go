@@ -0,0 +1,98 @@
+package core
+
+import (
+	"bufio"
+	"io"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"dsoob/backend/tools"
+)
+
+// Rebuilds the embedded Disposable Domain Blocklist from a list of domains and then immediately exits,
+// the source is either a local file or a URL with one domain per line. Rebuild the server afterwards.
+// Usage: debug_email_update_disposable [source]
+
+const (
+	DISPOSABLE_SOURCE = "https://raw.githubusercontent.com/disposable-email-domains/disposable-email-domains/main/disposable_email_blocklist.conf"
+)
+
+func DebugEmailUpdateDisposable() {
+	t := time.Now()
+	OUTPUT_FILE := "./include/DisposableDomains.txt"
+
+	// Parse Arguments
+	source := DISPOSABLE_SOURCE
+	i := slices.IndexFunc(os.Args, func(s string) bool {
+		return strings.EqualFold(s, "debug_email_update_disposable")
+	})
+	if i+1 < len(os.Args) {
+		source = os.Args[i+1]
+	}
+
+	// Open Source
+	var input io.ReadCloser
+	if strings.HasPrefix(source, "https://") || strings.HasPrefix(source, "http://") {
+		tools.LoggerEmail.Log(tools.INFO, "Downloading Domain List")
+		res, err := http.Get(source)
+		if err != nil {
+			tools.LoggerEmail.Log(tools.FATAL, "Error Downloading Domain List: %s", err)
+			return
+		}
+		if res.StatusCode != http.StatusOK {
+			tools.LoggerEmail.Log(tools.FATAL, "Error Downloading Domain List: %s", res.Status)
+			return
+		}
+		input = res.Body
+	} else {
+		f, err := os.Open(source)
+		if err != nil {
+			tools.LoggerEmail.Log(tools.FATAL, "Error Opening Domain List: %s", err)
+			return
+		}
+		input = f
+	}
+	defer input.Close()
+
+	// Read Domain List
+	// 	Domains are stored in their ASCII form to match normalized addresses
+	var (
+		domains = []string{}
+		line    = 0
+		scanner = bufio.NewScanner(input)
+	)
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		domain, err := tools.EmailDomainASCII(text)
+		if err != nil {
+			tools.LoggerEmail.Log(tools.WARN, "Skipping Invalid Domain on Line %d: %s", line, text)
+			continue
+		}
+		domains = append(domains, domain)
+	}
+	if err := scanner.Err(); err != nil {
+		tools.LoggerEmail.Log(tools.FATAL, "Error Reading Domain List: %s", err)
+		return
+	}
+	if len(domains) == 0 {
+		tools.LoggerEmail.Log(tools.FATAL, "No Domains Found")
+		return
+	}
+	slices.Sort(domains)
+	domains = slices.Compact(domains)
+
+	// Write Domain List
+	if err := os.WriteFile(OUTPUT_FILE, []byte(strings.Join(domains, "\n")+"\n"), 0644); err != nil {
+		tools.LoggerEmail.Log(tools.FATAL, "Error Writing Domain List: %s", err)
+		return
+	}
+
+	tools.LoggerEmail.Log(tools.INFO, "Wrote %d Domains, Completed in %s", len(domains), time.Since(t))
+}
@@ -0,0 +1,132 @@
+package core
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"os"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"dsoob/backend/tools"
+)
+
+// Builds the Breached Password Filter from a list of SHA-1 hashes and then immediately exits,
+// every line is either a hash or a hash and the number of times it was seen (HASH:COUNT).
+// Usage: debug_password_update_breached <hash_list> [minimum_count]
+
+const (
+	BREACHED_FALSE_POSITIVE = 0.001 // Chance of a Password being Rejected for no Reason
+)
+
+func DebugPasswordUpdateBreached() {
+	t := time.Now()
+	OUTPUT_FILE := path.Join(tools.DATA_DIRECTORY, "private", tools.BREACHED_PASSWORDS_FILE)
+
+	// Parse Arguments
+	i := slices.IndexFunc(os.Args, func(s string) bool {
+		return strings.EqualFold(s, "debug_password_update_breached")
+	})
+	if i+1 >= len(os.Args) {
+		fmt.Println("Usage: debug_password_update_breached <hash_list> [minimum_count]")
+		return
+	}
+	minimumCount := 1
+	if i+2 < len(os.Args) {
+		n, err := strconv.Atoi(os.Args[i+2])
+		if err != nil || n < 1 {
+			fmt.Println("Invalid Minimum Count")
+			return
+		}
+		minimumCount = n
+	}
+
+	// Read Hash List
+	// 	The list is read twice, once to size the filter and again to fill it
+	scan := func(fn func(digest [sha1.Size]byte)) {
+		f, err := os.Open(os.Args[i+1])
+		if err != nil {
+			tools.LoggerPassword.Log(tools.FATAL, "Error Opening Hash List: %s", err)
+			return
+		}
+		defer f.Close()
+
+		line := 0
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			line++
+			hash, count, found := strings.Cut(strings.TrimSpace(scanner.Text()), ":")
+			if hash == "" {
+				continue
+			}
+			if found {
+				n, err := strconv.Atoi(count)
+				if err != nil {
+					tools.LoggerPassword.Log(tools.FATAL, "Error Decoding Count on Line %d: %s", line, err)
+				}
+				if n < minimumCount {
+					continue
+				}
+			}
+			var digest [sha1.Size]byte
+			if n, err := hex.Decode(digest[:], []byte(hash)); err != nil || n != sha1.Size {
+				tools.LoggerPassword.Log(tools.FATAL, "Error Decoding Hash on Line %d", line)
+			}
+			fn(digest)
+		}
+		if err := scanner.Err(); err != nil {
+			tools.LoggerPassword.Log(tools.FATAL, "Error Reading Hash List: %s", err)
+		}
+	}
+
+	tools.LoggerPassword.Log(tools.INFO, "Counting Hashes")
+	entries := 0
+	scan(func(digest [sha1.Size]byte) { entries++ })
+	if entries == 0 {
+		tools.LoggerPassword.Log(tools.FATAL, "No Hashes Found")
+		return
+	}
+
+	// Size Filter
+	// 	Optimal amount of bits and hashes for the desired false positive rate
+	var (
+		size   = uint64(math.Ceil(-float64(entries) * math.Log(BREACHED_FALSE_POSITIVE) / (math.Ln2 * math.Ln2)))
+		hashes = uint32(math.Round(float64(size) / float64(entries) * math.Ln2))
+		bits   = make([]byte, (size+7)/8)
+	)
+	tools.LoggerPassword.Log(tools.INFO, "Filling Filter: %d Hashes, %d Bits, %d Hash Functions", entries, size, hashes)
+	scan(func(digest [sha1.Size]byte) {
+		for _, p := range tools.BreachedPositions(digest, size, hashes) {
+			bits[p/8] |= 1 << (p % 8)
+		}
+	})
+
+	// Write Filter
+	output, err := os.Create(OUTPUT_FILE)
+	if err != nil {
+		tools.LoggerPassword.Log(tools.FATAL, "Error Creating Filter File: %s", err)
+	}
+	defer output.Close()
+
+	writer := bufio.NewWriter(output)
+	if err := binary.Write(writer, binary.LittleEndian, size); err != nil {
+		tools.LoggerPassword.Log(tools.FATAL, "Error Writing Filter File: %s", err)
+	}
+	if err := binary.Write(writer, binary.LittleEndian, hashes); err != nil {
+		tools.LoggerPassword.Log(tools.FATAL, "Error Writing Filter File: %s", err)
+	}
+	if _, err := writer.Write(bits); err != nil {
+		tools.LoggerPassword.Log(tools.FATAL, "Error Writing Filter File: %s", err)
+	}
+	if err := writer.Flush(); err != nil {
+		tools.LoggerPassword.Log(tools.FATAL, "Error Writing Filter File: %s", err)
+	}
+
+	tools.LoggerPassword.Log(tools.INFO, "Completed in %s", time.Since(t))
+}
@@ -0,0 +1,69 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"dsoob/backend/tools"
+)
+
+// Grants Administrative Permissions to a User and then immediately exits
+// Usage: debug_user_permissions <user_id> <none|moderator|administrator|bitfield>
+
+func DebugUserPermissions() {
+
+	// Parse Arguments
+	i := slices.IndexFunc(os.Args, func(s string) bool {
+		return strings.EqualFold(s, "debug_user_permissions")
+	})
+	if i+2 >= len(os.Args) {
+		fmt.Println("Usage: debug_user_permissions <user_id> <none|moderator|administrator|bitfield>")
+		return
+	}
+	userID, err := strconv.ParseInt(os.Args[i+1], 10, 64)
+	if err != nil {
+		fmt.Printf("Invalid User ID: %s\n", err)
+		return
+	}
+	var permissions int64
+	switch strings.ToLower(os.Args[i+2]) {
+	case "none":
+		permissions = 0
+	case "moderator":
+		permissions = tools.ROLE_MODERATOR
+	case "administrator":
+		permissions = tools.ROLE_ADMINISTRATOR
+	default:
+		if permissions, err = strconv.ParseInt(os.Args[i+2], 10, 64); err != nil {
+			fmt.Printf("Invalid Permissions: %s\n", err)
+			return
+		}
+	}
+
+	// Update User
+	var stopWg sync.WaitGroup
+	stopCtx, stop := context.WithCancel(context.Background())
+	defer stopWg.Wait()
+	defer stop()
+	tools.DatabaseSetup(stopCtx, &stopWg)
+
+	tag, err := tools.Database.Exec(
+		"UPDATE user SET updated = CURRENT_TIMESTAMP, permissions = ? WHERE id = ?",
+		permissions,
+		userID,
+	)
+	if err != nil {
+		fmt.Printf("Database Error: %s\n", err)
+		return
+	}
+	if c, _ := tag.RowsAffected(); c == 0 {
+		fmt.Printf("Unknown User: %d\n", userID)
+		return
+	}
+	fmt.Printf("Updated User %d Permissions to %d\n", userID, permissions)
+}
@@ -9,92 +9,251 @@ import (
 
 func SetupMux() *http.ServeMux {
 
+	// NOTE: Proof of Work carries the load against automated signups when enabled,
+	// so the per-address limit is relaxed for users sharing an address (e.g. carrier-grade NAT)
+	signupLimit := int64(3)
+	if tools.POW_DIFFICULTY > 0 {
+		signupLimit = tools.POW_SIGNUP_LIMIT
+	}
+
 	var (
 		mux                 = http.NewServeMux()
-		limitFILE           = tools.NewBodyLimit(8 * 1024 * 1024)   // 8MB
-		limitJSON           = tools.NewBodyLimit(16 * 1024)         // 16KB
-		limitBLOB           = tools.NewBodyLimit(32 * 1024)         // 32KB
-		rateAuthSignup      = tools.NewRatelimit(3, 24*time.Hour)   // Limit: New Accounts
-		rateAuthLogin       = tools.NewRatelimit(5, 5*time.Minute)  // Limit: Login Attempts
-		rateAuthVerify      = tools.NewRatelimit(5, 5*time.Minute)  // Limit: Escalation / Password Reset Attempts
-		ratePublicRead      = tools.NewRatelimit(50, 1*time.Minute) // Limit: Public Requests
-		ratePrivateRead     = tools.NewRatelimit(50, 5*time.Minute) // Limit: User Read Requests
-		ratePrivateWrite    = tools.NewRatelimit(10, 5*time.Minute) // Limit: User Write Requests
-		ratePrivateSpammy   = tools.NewRatelimit(5, 30*time.Minute) // Limit: Requests that should not be spammed
-		rateImagesReadWrite = tools.NewRatelimit(10, 5*time.Minute) // Limit: User Images
+		limitFILE           = tools.NewBodyLimit(8 * 1024 * 1024)           // 8MB
+		limitJSON           = tools.NewBodyLimit(16 * 1024)                 // 16KB
+		limitBLOB           = tools.NewBodyLimit(32 * 1024)                 // 32KB
+		rateAuthSignup      = tools.NewRatelimit(signupLimit, 24*time.Hour) // Limit: New Accounts
+		rateAuthLogin       = tools.NewRatelimit(5, 5*time.Minute)          // Limit: Login Attempts
+		rateAuthVerify      = tools.NewRatelimit(5, 5*time.Minute)          // Limit: Escalation / Password Reset Attempts
+		rateAuthPairing     = tools.NewRatelimit(60, 5*time.Minute)         // Limit: Device Pairing Polls
+		rateAuthRefresh     = tools.NewRatelimit(20, 5*time.Minute)         // Limit: Access Token Refreshes
+		ratePublicRead      = tools.NewRatelimit(50, 1*time.Minute)         // Limit: Public Requests
+		ratePrivateRead     = tools.NewRatelimit(50, 5*time.Minute)         // Limit: User Read Requests
+		ratePrivateWrite    = tools.NewRatelimit(10, 5*time.Minute)         // Limit: User Write Requests
+		ratePrivateSpammy   = tools.NewRatelimit(5, 30*time.Minute)         // Limit: Requests that should not be spammed
+		rateImagesReadWrite = tools.NewRatelimit(10, 5*time.Minute)         // Limit: User Images
+
+		// NOTE: Every route using UseSession must be followed by a scope,
+		// otherwise applications would have unrestricted access to it!
+		scopeAccount       = tools.NewScope(tools.SCOPE_ACCOUNT)        // Scope: Never granted to Applications
+		scopeProfileRead   = tools.NewScope(tools.SCOPE_PROFILE_READ)   // Scope: Read Profile
+		scopeProfileWrite  = tools.NewScope(tools.SCOPE_PROFILE_WRITE)  // Scope: Edit Profile
+		scopeSettingsRead  = tools.NewScope(tools.SCOPE_SETTINGS_READ)  // Scope: Read Settings
+		scopeSettingsWrite = tools.NewScope(tools.SCOPE_SETTINGS_WRITE) // Scope: Write Settings
+
+		// NOTE: Administrative routes must also use scopeAccount
+		permUsersRead     = tools.NewPermission(tools.PERMISSION_USERS_READ)     // Permission: Search Users
+		permUsersSuspend  = tools.NewPermission(tools.PERMISSION_USERS_SUSPEND)  // Permission: Suspend Users
+		permUsersSecurity = tools.NewPermission(tools.PERMISSION_USERS_SECURITY) // Permission: Manage User Security
+		permUsersContent  = tools.NewPermission(tools.PERMISSION_USERS_CONTENT)  // Permission: Delete User Content
+		permUsersInvite   = tools.NewPermission(tools.PERMISSION_USERS_INVITE)   // Permission: Create Invites
 	)
 
 	// Auth
+	mux.Handle("/auth/proof", tools.MethodHandler{
+		http.MethodGet: tools.Chain(routes.GET_Auth_Proof, ratePublicRead),
+	})
 	mux.Handle("/auth/login", tools.MethodHandler{
-		http.MethodPost: tools.Chain(routes.POST_Auth_Login, rateAuthLogin, limitJSON),
+		http.MethodPost: tools.Chain(routes.POST_Auth_Login, rateAuthLogin, limitJSON, tools.UseProofOfWork),
 	})
 	mux.Handle("/auth/signup", tools.MethodHandler{
-		http.MethodPost: tools.Chain(routes.POST_Auth_Signup, rateAuthSignup, limitJSON),
+		http.MethodPost: tools.Chain(routes.POST_Auth_Signup, rateAuthSignup, limitJSON, tools.UseProofOfWork),
+	})
+	mux.Handle("/auth/login/email", tools.MethodHandler{
+		http.MethodPost: tools.Chain(routes.POST_Auth_Login_Email, rateAuthVerify, limitJSON, tools.UseProofOfWork),
+	})
+	mux.Handle("/auth/login/email/verify", tools.MethodHandler{
+		http.MethodPost: tools.Chain(routes.POST_Auth_Login_Email_Verify, rateAuthLogin, limitJSON),
 	})
 	mux.Handle("/auth/logout", tools.MethodHandler{
-		http.MethodPost: tools.Chain(routes.POST_Auth_Logout, rateAuthLogin, limitJSON, tools.UseSession),
+		http.MethodPost: tools.Chain(routes.POST_Auth_Logout, rateAuthLogin, limitJSON, tools.UseSession, scopeAccount),
+	})
+	mux.Handle("/auth/refresh", tools.MethodHandler{
+		http.MethodPost: tools.Chain(routes.POST_Auth_Refresh, rateAuthRefresh, limitJSON),
 	})
 	mux.Handle("/auth/password-reset", tools.MethodHandler{
-		http.MethodPost:  tools.Chain(routes.POST_Auth_ResetPassword, rateAuthVerify, limitJSON),
+		http.MethodPost:  tools.Chain(routes.POST_Auth_ResetPassword, rateAuthVerify, limitJSON, tools.UseProofOfWork),
 		http.MethodPatch: tools.Chain(routes.PATCH_Auth_ResetPassword, rateAuthVerify, limitJSON),
 	})
+	mux.Handle("/auth/account-recovery", tools.MethodHandler{
+		http.MethodPost:   tools.Chain(routes.POST_Auth_AccountRecovery, rateAuthVerify, limitJSON),
+		http.MethodDelete: tools.Chain(routes.DELETE_Auth_AccountRecovery, rateAuthVerify, limitJSON),
+	})
+	mux.Handle("/auth/restore", tools.MethodHandler{
+		http.MethodPost: tools.Chain(routes.POST_Auth_Restore, rateAuthVerify, limitJSON),
+	})
 	mux.Handle("/auth/verify-login", tools.MethodHandler{
 		http.MethodPost: tools.Chain(routes.POST_Auth_VerifyLogin, rateAuthVerify, limitJSON),
 	})
 	mux.Handle("/auth/verify-email", tools.MethodHandler{
 		http.MethodPost: tools.Chain(routes.POST_Auth_VerifyEmail, rateAuthVerify, limitJSON),
 	})
+	mux.Handle("/auth/verify-email-change", tools.MethodHandler{
+		http.MethodPost: tools.Chain(routes.POST_Auth_VerifyEmailChange, rateAuthVerify, limitJSON),
+	})
+	mux.Handle("/auth/revert-email-change", tools.MethodHandler{
+		http.MethodPost: tools.Chain(routes.POST_Auth_RevertEmailChange, rateAuthVerify, limitJSON),
+	})
+	mux.Handle("/auth/pairing", tools.MethodHandler{
+		http.MethodPost: tools.Chain(routes.POST_Auth_Pairing, rateAuthLogin, limitJSON),
+	})
+	mux.Handle("/auth/pairing/poll", tools.MethodHandler{
+		http.MethodPost: tools.Chain(routes.POST_Auth_Pairing_Poll, rateAuthPairing, limitJSON),
+	})
 
 	// User
 	mux.Handle("/users/@me", tools.MethodHandler{
-		http.MethodGet:    tools.Chain(routes.GET_Users_Me, ratePrivateRead, tools.UseSession),
-		http.MethodPatch:  tools.Chain(routes.PATCH_Users_Me, ratePrivateWrite, limitJSON, tools.UseSession),
-		http.MethodDelete: tools.Chain(routes.DELETE_Users_Me, ratePrivateWrite, tools.UseSession),
+		http.MethodGet:    tools.Chain(routes.GET_Users_Me, ratePrivateRead, tools.UseSession, scopeProfileRead),
+		http.MethodPatch:  tools.Chain(routes.PATCH_Users_Me, ratePrivateWrite, limitJSON, tools.UseSession, scopeProfileWrite),
+		http.MethodDelete: tools.Chain(routes.DELETE_Users_Me, ratePrivateWrite, tools.UseSession, scopeAccount),
+	})
+	mux.Handle("/users/@me/username", tools.MethodHandler{
+		http.MethodPatch: tools.Chain(routes.PATCH_Users_Me_Username, ratePrivateSpammy, limitJSON, tools.UseSession, scopeAccount),
+	})
+	mux.Handle("/users/@me/export", tools.MethodHandler{
+		http.MethodPost: tools.Chain(routes.POST_Users_Me_Export, ratePrivateSpammy, tools.UseSession, scopeAccount),
 	})
 	mux.Handle("/users/@me/avatar", tools.MethodHandler{
-		http.MethodPut:    tools.Chain(routes.PUT_Users_Me_Avatar, rateImagesReadWrite, limitFILE, tools.UseSession),
-		http.MethodDelete: tools.Chain(routes.DELETE_Users_Me_Avatar, rateImagesReadWrite, tools.UseSession),
+		http.MethodPut:    tools.Chain(routes.PUT_Users_Me_Avatar, rateImagesReadWrite, limitFILE, tools.UseSession, scopeProfileWrite),
+		http.MethodDelete: tools.Chain(routes.DELETE_Users_Me_Avatar, rateImagesReadWrite, tools.UseSession, scopeProfileWrite),
 	})
 	mux.Handle("/users/@me/banner", tools.MethodHandler{
-		http.MethodPut:    tools.Chain(routes.PUT_Users_Me_Banner, rateImagesReadWrite, limitFILE, tools.UseSession),
-		http.MethodDelete: tools.Chain(routes.DELETE_Users_Me_Banner, rateImagesReadWrite, tools.UseSession),
+		http.MethodPut:    tools.Chain(routes.PUT_Users_Me_Banner, rateImagesReadWrite, limitFILE, tools.UseSession, scopeProfileWrite),
+		http.MethodDelete: tools.Chain(routes.DELETE_Users_Me_Banner, rateImagesReadWrite, tools.UseSession, scopeProfileWrite),
 	})
 	mux.Handle("/users/@me/security/sessions", tools.MethodHandler{
-		http.MethodGet: tools.Chain(routes.GET_Users_Me_Security_Sessions, ratePrivateRead, tools.UseSession),
+		http.MethodGet:    tools.Chain(routes.GET_Users_Me_Security_Sessions, ratePrivateRead, tools.UseSession, scopeAccount),
+		http.MethodDelete: tools.Chain(routes.DELETE_Users_Me_Security_Sessions, ratePrivateWrite, tools.UseSession, scopeAccount),
 	})
 	mux.Handle("/users/@me/security/sessions/{id}", tools.MethodHandler{
-		http.MethodDelete: tools.Chain(routes.DELETE_Users_Me_Security_Sessions_ID, ratePrivateWrite, tools.UseSession),
+		http.MethodDelete: tools.Chain(routes.DELETE_Users_Me_Security_Sessions_ID, ratePrivateWrite, tools.UseSession, scopeAccount),
+	})
+	mux.Handle("/users/@me/security/events", tools.MethodHandler{
+		http.MethodGet: tools.Chain(routes.GET_Users_Me_Security_Events, ratePrivateRead, tools.UseSession, scopeAccount),
+	})
+	mux.Handle("/users/@me/security/tokens", tools.MethodHandler{
+		http.MethodGet:  tools.Chain(routes.GET_Users_Me_Security_Tokens, ratePrivateRead, tools.UseSession, scopeAccount),
+		http.MethodPost: tools.Chain(routes.POST_Users_Me_Security_Tokens, ratePrivateWrite, limitJSON, tools.UseSession, scopeAccount),
+	})
+	mux.Handle("/users/@me/security/tokens/{id}", tools.MethodHandler{
+		http.MethodDelete: tools.Chain(routes.DELETE_Users_Me_Security_Tokens_ID, ratePrivateWrite, tools.UseSession, scopeAccount),
+	})
+	mux.Handle("/users/@me/security/pairing", tools.MethodHandler{
+		http.MethodPost: tools.Chain(routes.POST_Users_Me_Security_Pairing, ratePrivateWrite, limitJSON, tools.UseSession, scopeAccount),
 	})
 	mux.Handle("/users/@me/security/mfa/setup", tools.MethodHandler{
-		http.MethodGet:    tools.Chain(routes.GET_Users_Me_Security_MFA_Setup, ratePrivateRead, tools.UseSession),
-		http.MethodPost:   tools.Chain(routes.POST_Users_Me_Security_MFA_Setup, ratePrivateWrite, limitJSON, tools.UseSession),
-		http.MethodDelete: tools.Chain(routes.DELETE_Users_Me_Security_MFA_Setup, ratePrivateWrite, tools.UseSession),
+		http.MethodGet:    tools.Chain(routes.GET_Users_Me_Security_MFA_Setup, ratePrivateRead, tools.UseSession, scopeAccount),
+		http.MethodPost:   tools.Chain(routes.POST_Users_Me_Security_MFA_Setup, ratePrivateWrite, limitJSON, tools.UseSession, scopeAccount),
+		http.MethodDelete: tools.Chain(routes.DELETE_Users_Me_Security_MFA_Setup, ratePrivateWrite, tools.UseSession, scopeAccount),
 	})
 	mux.Handle("/users/@me/security/mfa/codes", tools.MethodHandler{
-		http.MethodGet:    tools.Chain(routes.GET_Users_Me_Security_MFA_Codes, ratePrivateRead, tools.UseSession),
-		http.MethodDelete: tools.Chain(routes.DELETE_Users_Me_Security_MFA_Codes, ratePrivateWrite, tools.UseSession),
+		http.MethodGet:    tools.Chain(routes.GET_Users_Me_Security_MFA_Codes, ratePrivateRead, tools.UseSession, scopeAccount),
+		http.MethodDelete: tools.Chain(routes.DELETE_Users_Me_Security_MFA_Codes, ratePrivateWrite, tools.UseSession, scopeAccount),
 	})
 	mux.Handle("/users/@me/security/escalate", tools.MethodHandler{
-		http.MethodPost: tools.Chain(routes.POST_Users_Me_Security_Escalate, rateAuthVerify, limitJSON, tools.UseSession),
+		http.MethodPost: tools.Chain(routes.POST_Users_Me_Security_Escalate, rateAuthVerify, limitJSON, tools.UseSession, scopeAccount),
 	})
 	mux.Handle("/users/@me/security/password", tools.MethodHandler{
-		http.MethodPatch: tools.Chain(routes.PATCH_Users_Me_Security_Password, ratePrivateWrite, limitJSON, tools.UseSession),
+		http.MethodPatch: tools.Chain(routes.PATCH_Users_Me_Security_Password, ratePrivateWrite, limitJSON, tools.UseSession, scopeAccount),
 	})
 	mux.Handle("/users/@me/security/email", tools.MethodHandler{
-		http.MethodPost:  tools.Chain(routes.POST_Users_Me_Security_Email, ratePrivateSpammy, tools.UseSession),
-		http.MethodPatch: tools.Chain(routes.PATCH_Users_Me_Security_Email, ratePrivateSpammy, limitJSON, tools.UseSession),
+		http.MethodPost:  tools.Chain(routes.POST_Users_Me_Security_Email, ratePrivateSpammy, tools.UseSession, scopeAccount),
+		http.MethodPatch: tools.Chain(routes.PATCH_Users_Me_Security_Email, ratePrivateSpammy, limitJSON, tools.UseSession, scopeAccount),
+	})
+	mux.Handle("/users/@me/applications", tools.MethodHandler{
+		http.MethodGet:  tools.Chain(routes.GET_Users_Me_Applications, ratePrivateRead, tools.UseSession, scopeAccount),
+		http.MethodPost: tools.Chain(routes.POST_Users_Me_Applications, ratePrivateWrite, limitJSON, tools.UseSession, scopeAccount),
+	})
+	mux.Handle("/users/@me/applications/{id}", tools.MethodHandler{
+		http.MethodPatch:  tools.Chain(routes.PATCH_Users_Me_Applications_ID, ratePrivateWrite, limitJSON, tools.UseSession, scopeAccount),
+		http.MethodDelete: tools.Chain(routes.DELETE_Users_Me_Applications_ID, ratePrivateWrite, tools.UseSession, scopeAccount),
+	})
+	mux.Handle("/users/@me/applications/{id}/secret", tools.MethodHandler{
+		http.MethodPost: tools.Chain(routes.POST_Users_Me_Applications_ID_Secret, ratePrivateSpammy, tools.UseSession, scopeAccount),
+	})
+	mux.Handle("/users/@me/invites", tools.MethodHandler{
+		http.MethodGet:  tools.Chain(routes.GET_Users_Me_Invites, ratePrivateRead, tools.UseSession, scopeAccount),
+		http.MethodPost: tools.Chain(routes.POST_Users_Me_Invites, ratePrivateWrite, limitJSON, tools.UseSession, scopeAccount),
+	})
+	mux.Handle("/users/@me/invites/{id}", tools.MethodHandler{
+		http.MethodDelete: tools.Chain(routes.DELETE_Users_Me_Invites_ID, ratePrivateWrite, tools.UseSession, scopeAccount),
 	})
 	mux.Handle("/users/@me/settings", tools.MethodHandler{
-		http.MethodGet: tools.Chain(routes.GET_Users_Me_Settings, ratePrivateRead, tools.UseSession),
-		http.MethodPut: tools.Chain(routes.PUT_Users_Me_Settings, ratePrivateWrite, limitBLOB, tools.UseSession),
+		http.MethodGet:    tools.Chain(routes.GET_Users_Me_Settings, ratePrivateRead, tools.UseSession, scopeSettingsRead),
+		http.MethodPut:    tools.Chain(routes.PUT_Users_Me_Settings, ratePrivateWrite, limitBLOB, tools.UseSession, scopeSettingsWrite),
+		http.MethodDelete: tools.Chain(routes.DELETE_Users_Me_Settings, ratePrivateWrite, tools.UseSession, scopeSettingsWrite),
+	})
+	mux.Handle("/users/@me/settings/namespaces", tools.MethodHandler{
+		http.MethodGet: tools.Chain(routes.GET_Users_Me_Settings_Namespaces, ratePrivateRead, tools.UseSession, scopeSettingsRead),
+	})
+
+	// NOTE: Namespaced settings share their handlers with the default namespace above,
+	// history is only reachable through a namespace to avoid ambiguous paths, use 'default'
+	mux.Handle("/users/@me/settings/{namespace}", tools.MethodHandler{
+		http.MethodGet:    tools.Chain(routes.GET_Users_Me_Settings, ratePrivateRead, tools.UseSession, scopeSettingsRead),
+		http.MethodPut:    tools.Chain(routes.PUT_Users_Me_Settings, ratePrivateWrite, limitBLOB, tools.UseSession, scopeSettingsWrite),
+		http.MethodDelete: tools.Chain(routes.DELETE_Users_Me_Settings, ratePrivateWrite, tools.UseSession, scopeSettingsWrite),
+	})
+	mux.Handle("/users/@me/settings/{namespace}/history", tools.MethodHandler{
+		http.MethodGet: tools.Chain(routes.GET_Users_Me_Settings_History, ratePrivateRead, tools.UseSession, scopeSettingsRead),
+	})
+	mux.Handle("/users/@me/settings/{namespace}/history/{id}", tools.MethodHandler{
+		http.MethodPost: tools.Chain(routes.POST_Users_Me_Settings_History_ID, ratePrivateWrite, tools.UseSession, scopeSettingsWrite),
+	})
+
+	// Admin
+	mux.Handle("/admin/users", tools.MethodHandler{
+		http.MethodGet: tools.Chain(routes.GET_Admin_Users, ratePrivateRead, tools.UseSession, scopeAccount, permUsersRead),
+	})
+	mux.Handle("/admin/users/{id}/sessions", tools.MethodHandler{
+		http.MethodGet: tools.Chain(routes.GET_Admin_Users_ID_Sessions, ratePrivateRead, tools.UseSession, scopeAccount, permUsersRead),
+	})
+	mux.Handle("/admin/users/{id}/suspension", tools.MethodHandler{
+		http.MethodPut:    tools.Chain(routes.PUT_Admin_Users_ID_Suspension, ratePrivateWrite, limitJSON, tools.UseSession, scopeAccount, permUsersSuspend, tools.UseAdminTarget),
+		http.MethodDelete: tools.Chain(routes.DELETE_Admin_Users_ID_Suspension, ratePrivateWrite, tools.UseSession, scopeAccount, permUsersSuspend, tools.UseAdminTarget),
+	})
+	mux.Handle("/admin/users/{id}/password-reset", tools.MethodHandler{
+		http.MethodPost: tools.Chain(routes.POST_Admin_Users_ID_ResetPassword, ratePrivateWrite, tools.UseSession, scopeAccount, permUsersSecurity, tools.UseAdminTarget),
+	})
+	mux.Handle("/admin/users/{id}/mfa", tools.MethodHandler{
+		http.MethodDelete: tools.Chain(routes.DELETE_Admin_Users_ID_MFA, ratePrivateWrite, tools.UseSession, scopeAccount, permUsersSecurity, tools.UseAdminTarget),
+	})
+	mux.Handle("/admin/users/{id}/avatar", tools.MethodHandler{
+		http.MethodDelete: tools.Chain(routes.DELETE_Admin_Users_ID_Avatar, ratePrivateWrite, limitJSON, tools.UseSession, scopeAccount, permUsersContent, tools.UseAdminTarget),
+	})
+	mux.Handle("/admin/users/{id}/banner", tools.MethodHandler{
+		http.MethodDelete: tools.Chain(routes.DELETE_Admin_Users_ID_Banner, ratePrivateWrite, limitJSON, tools.UseSession, scopeAccount, permUsersContent, tools.UseAdminTarget),
+	})
+	mux.Handle("/admin/invites", tools.MethodHandler{
+		http.MethodPost: tools.Chain(routes.POST_Admin_Invites, ratePrivateWrite, limitJSON, tools.UseSession, scopeAccount, permUsersInvite),
 	})
 
 	// Public
 	mux.Handle("/users/bulk", tools.MethodHandler{
 		http.MethodPost: tools.Chain(routes.POST_Users_Bulk, ratePublicRead),
 	})
-	mux.Handle("/users/{id}/keychain", tools.MethodHandler{
-		http.MethodGet: tools.Chain(routes.GET_Users_ID_Keychain, ratePublicRead),
+	mux.Handle("/users/changes", tools.MethodHandler{
+		http.MethodGet: tools.Chain(routes.GET_Users_Changes, ratePublicRead),
+	})
+	mux.Handle("/users/lookup", tools.MethodHandler{
+		http.MethodPost: tools.Chain(routes.POST_Users_Lookup, ratePublicRead, limitJSON),
+	})
+	mux.Handle("/users/{id}", tools.MethodHandler{
+		http.MethodGet: tools.Chain(routes.GET_Users_ID, ratePublicRead),
+	})
+
+	mux.Handle("/users/by-username/{username}", tools.MethodHandler{
+		http.MethodGet: tools.Chain(routes.GET_Users_ByUsername_Username, ratePublicRead),
+	})
+
+	// NOTE: '/users/{id}/keychain' would conflict with the pattern above (e.g. '/users/by-username/keychain')
+	// so sub-resources of a user share a less specific pattern and are looked up by name instead
+	mux.Handle("/users/{id}/{resource}", tools.ResourceHandler{
+		"keychain": tools.MethodHandler{
+			http.MethodGet: tools.Chain(routes.GET_Users_ID_Keychain, ratePublicRead),
+		},
+	})
+	mux.Handle("/exports/{token}", tools.MethodHandler{
+		http.MethodGet: tools.Chain(routes.GET_Exports_Token, rateAuthVerify),
 	})
 
 	// Default 404 Handler
@@ -7,6 +7,7 @@ require (
 	github.com/mattn/go-sqlite3 v1.14.34
 	golang.org/x/crypto v0.46.0
 	golang.org/x/image v0.32.0
+	golang.org/x/net v0.48.0
 )
 
 require (
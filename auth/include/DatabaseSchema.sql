@@ -12,26 +12,28 @@ CREATE TABLE IF NOT EXISTS user (
 
     -- Account
     email_address       TEXT            NOT NULL UNIQUE,                            -- User Email Address
+    email_normalized    TEXT            UNIQUE,                                     -- Normalized Email Address (see EmailNormalize)
     email_verified      BOOLEAN         NOT NULL DEFAULT 0,                         -- Email Verified?
     ip_address          TEXT            NOT NULL DEFAULT '',                        -- New Login IP Address
     mfa_enabled         BOOLEAN         NOT NULL DEFAULT 0,                         -- MFA Enabled?
     mfa_secret          TEXT,                                                       -- MFA Secret Key
     mfa_codes           TEXT            NOT NULL DEFAULT '',                        -- [ARRAY] MFA Recovery Codes
     mfa_codes_used      INT             NOT NULL DEFAULT 0,                         -- MFA Exhausted Recovery Code Bitfield
+    mfa_removal_at      TIMESTAMP,                                                  -- MFA Removal Requested At (Lost Authenticator and Codes)
+    token_mfa_removal   TEXT            UNIQUE,                                     -- Cancel MFA Removal Token
     password_hash       TEXT,                                                       -- Active Password Hash
     password_history    TEXT            NOT NULL DEFAULT '',                        -- [ARRAY] Past Password Hashes
     token_verify        TEXT            UNIQUE,                                     -- Verify Email Token
     token_verify_eat    TIMESTAMP,                                                  -- Verify Email Token Expires At
-    token_login         TEXT            UNIQUE,                                     -- New Login Token
-    token_login_data    TEXT,                                                       -- New Login Token Arbitrary Data
-    token_login_eat     TIMESTAMP,                                                  -- New Login Token Expires At
-    token_reset         TEXT            UNIQUE,                                     -- Password Reset Token
-    token_reset_eat     TIMESTAMP,                                                  -- Password Reset Token Expires At
-    token_passcode      TEXT,                                                       -- Random Escalation Code
-    token_passcode_eat  TIMESTAMP,                                                  -- Random Escalation Code Expires At
+    permissions         INT             NOT NULL DEFAULT 0,                         -- Administrative Permission Bitfield
+    suspended_at        TIMESTAMP,                                                  -- Suspended At (NULL if not Suspended)
+    suspended_reason    TEXT,                                                       -- Suspension Reason
+    deleted_at          TIMESTAMP,                                                  -- Deletion Requested At (NULL if not Deleted)
+    token_restore       TEXT            UNIQUE,                                     -- Cancel Account Deletion Token
+    invited_by          INTEGER,                                                    -- Inviting User ID (NULL if not Invited)
 
     -- Profile
-    username            TEXT            NOT NULL UNIQUE,                            -- Username
+    username            TEXT            NOT NULL UNIQUE COLLATE NOCASE,             -- Username
     displayname         TEXT            NOT NULL,                                   -- Nickname
     subtitle            TEXT,                                                       -- Pronouns
     biography           TEXT,                                                       -- Biography
@@ -39,7 +41,8 @@ CREATE TABLE IF NOT EXISTS user (
     banner_hash         TEXT,                                                       -- Banner Image Hash
     accent_banner       INT,                                                        -- Banner Color
     accent_border       INT,                                                        -- Border Color
-    accent_background   INT                                                         -- Background Color
+    accent_background   INT,                                                        -- Background Color
+    revision            INTEGER         NOT NULL DEFAULT 0                          -- Latest Profile Revision
 );
 
 CREATE TABLE IF NOT EXISTS user_session (
@@ -47,12 +50,174 @@ CREATE TABLE IF NOT EXISTS user_session (
     created             TIMESTAMP       NOT NULL DEFAULT CURRENT_TIMESTAMP,         -- Created At
     updated             TIMESTAMP       NOT NULL DEFAULT CURRENT_TIMESTAMP,         -- Updated At
     user_id             INTEGER         NOT NULL,                                   -- Relevant User ID
-    token               TEXT            NOT NULL UNIQUE,                            -- Session Token
+    token               TEXT            NOT NULL UNIQUE,                            -- Session Refresh Token
     elevated_until      TIMESTAMP       NOT NULL DEFAULT CURRENT_TIMESTAMP,         -- Elevated Until UNIX Timestamp
+    elevated_method     TEXT            NOT NULL DEFAULT '',                        -- Method used for Elevation
+    elevated_actions    TEXT            NOT NULL DEFAULT '',                        -- Actions permitted by Elevation (Array)
     device_ip_address   TEXT            NOT NULL,                                   -- IP Address of Device
     device_user_agent   TEXT            NOT NULL,                                   -- User Agent of Device
     device_public_key   TEXT            NOT NULL,                                   -- Device Public Key
     FOREIGN KEY (user_id) REFERENCES user (id) ON DELETE CASCADE
 );
 
+-- Usernames are unique regardless of case, older databases lack the column collation
+CREATE UNIQUE INDEX IF NOT EXISTS idx_user_username ON user (username COLLATE NOCASE);
+
 CREATE INDEX IF NOT EXISTS idx_sessions_user ON user_session (user_id);
+
+CREATE TABLE IF NOT EXISTS user_session_retired (
+    token               TEXT            NOT NULL PRIMARY KEY,                       -- Previously Used Refresh Token
+    created             TIMESTAMP       NOT NULL DEFAULT CURRENT_TIMESTAMP,         -- Retired At
+    session_id          INTEGER         NOT NULL,                                   -- Relevant Session ID
+    FOREIGN KEY (session_id) REFERENCES user_session (id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_sessions_retired_session ON user_session_retired (session_id);
+
+CREATE TABLE IF NOT EXISTS token_key (
+    id                  INTEGER         NOT NULL PRIMARY KEY,                       -- Signing Key ID
+    created             TIMESTAMP       NOT NULL DEFAULT CURRENT_TIMESTAMP,         -- Created At
+    secret              BLOB            NOT NULL                                    -- Signing Key Secret
+);
+
+CREATE TABLE IF NOT EXISTS token_revoked (
+    session_id          INTEGER         NOT NULL PRIMARY KEY,                       -- Revoked Session ID
+    expires             TIMESTAMP       NOT NULL                                    -- Revoked Until (Outstanding Access Tokens have expired)
+);
+
+CREATE TABLE IF NOT EXISTS user_pairing (
+    id                  INTEGER         NOT NULL PRIMARY KEY,                       -- Pairing ID
+    created             TIMESTAMP       NOT NULL DEFAULT CURRENT_TIMESTAMP,         -- Created At
+    expires             TIMESTAMP       NOT NULL,                                   -- Expires At
+    code                TEXT            NOT NULL UNIQUE,                            -- Pairing Code (Shown to User)
+    token               TEXT            NOT NULL UNIQUE,                            -- Polling Token (Kept by Device)
+    user_id             INTEGER,                                                    -- Approving User ID
+    device_ip_address   TEXT            NOT NULL,                                   -- IP Address of Device
+    device_user_agent   TEXT            NOT NULL,                                   -- User Agent of Device
+    device_public_key   TEXT            NOT NULL,                                   -- Device Public Key
+    FOREIGN KEY (user_id) REFERENCES user (id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS application (
+    id                  INTEGER         NOT NULL PRIMARY KEY,                       -- Application ID
+    created             TIMESTAMP       NOT NULL DEFAULT CURRENT_TIMESTAMP,         -- Created At
+    updated             TIMESTAMP       NOT NULL DEFAULT CURRENT_TIMESTAMP,         -- Updated At
+    owner_id            INTEGER         NOT NULL,                                   -- Owner User ID
+    name                TEXT            NOT NULL,                                   -- Application Name
+    secret              TEXT            NOT NULL UNIQUE,                            -- Bot Token
+    scopes              TEXT            NOT NULL DEFAULT '',                        -- [ARRAY] Granted Scopes
+    FOREIGN KEY (owner_id) REFERENCES user (id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_application_owner ON application (owner_id);
+
+CREATE TABLE IF NOT EXISTS user_token (
+    id                  INTEGER         NOT NULL PRIMARY KEY,                       -- Token ID
+    created             TIMESTAMP       NOT NULL DEFAULT CURRENT_TIMESTAMP,         -- Created At
+    expires             TIMESTAMP,                                                  -- Expires At (NULL = Never)
+    user_id             INTEGER         NOT NULL,                                   -- Relevant User ID
+    name                TEXT            NOT NULL,                                   -- Token Name
+    token               TEXT            NOT NULL UNIQUE,                            -- Personal Access Token
+    scopes              TEXT            NOT NULL DEFAULT '',                        -- [ARRAY] Granted Scopes
+    FOREIGN KEY (user_id) REFERENCES user (id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_token_user ON user_token (user_id);
+
+CREATE TABLE IF NOT EXISTS user_invite (
+    id                  INTEGER         NOT NULL PRIMARY KEY,                       -- Invite ID
+    created             TIMESTAMP       NOT NULL DEFAULT CURRENT_TIMESTAMP,         -- Created At
+    expires             TIMESTAMP,                                                  -- Expires At (NULL = Never)
+    user_id             INTEGER         NOT NULL,                                   -- Creator User ID
+    code                TEXT            NOT NULL UNIQUE,                            -- Invite Code
+    uses                INT             NOT NULL DEFAULT 0,                         -- Times Used
+    uses_limit          INT             NOT NULL,                                   -- Maximum Uses
+    FOREIGN KEY (user_id) REFERENCES user (id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_invite_user ON user_invite (user_id);
+
+CREATE TABLE IF NOT EXISTS user_export (
+    id                  INTEGER         NOT NULL PRIMARY KEY,                       -- Export ID
+    created             TIMESTAMP       NOT NULL DEFAULT CURRENT_TIMESTAMP,         -- Created At
+    expires             TIMESTAMP       NOT NULL,                                   -- Expires At
+    user_id             INTEGER         NOT NULL,                                   -- Relevant User ID
+    token               TEXT            NOT NULL UNIQUE,                            -- Download Token
+    FOREIGN KEY (user_id) REFERENCES user (id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_export_user ON user_export (user_id);
+
+CREATE TABLE IF NOT EXISTS user_event (
+    id                  INTEGER         NOT NULL PRIMARY KEY,                       -- Event ID
+    created             TIMESTAMP       NOT NULL DEFAULT CURRENT_TIMESTAMP,         -- Created At
+    user_id             INTEGER         NOT NULL,                                   -- Relevant User ID
+    type                TEXT            NOT NULL,                                   -- Event Type
+    detail              TEXT            NOT NULL DEFAULT '',                        -- Event Details (e.g. Method Used)
+    ip_address          TEXT            NOT NULL,                                   -- IP Address of Device
+    user_agent          TEXT            NOT NULL,                                   -- User Agent of Device
+    FOREIGN KEY (user_id) REFERENCES user (id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_event_user ON user_event (user_id, id);
+
+CREATE TABLE IF NOT EXISTS user_username (
+    id                  INTEGER         NOT NULL PRIMARY KEY,                       -- Change ID
+    created             TIMESTAMP       NOT NULL DEFAULT CURRENT_TIMESTAMP,         -- Changed At
+    reserved_until      TIMESTAMP       NOT NULL,                                   -- Reserved for Previous Owner Until
+    user_id             INTEGER         NOT NULL,                                   -- Relevant User ID
+    username            TEXT            NOT NULL COLLATE NOCASE,                    -- Previous Username
+    FOREIGN KEY (user_id) REFERENCES user (id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_username_user ON user_username (user_id);
+CREATE INDEX IF NOT EXISTS idx_username_username ON user_username (username);
+
+CREATE TABLE IF NOT EXISTS user_revision (
+    revision            INTEGER         NOT NULL PRIMARY KEY AUTOINCREMENT,         -- Profile Revision
+    user_id             INTEGER         NOT NULL UNIQUE,                            -- Relevant User ID (Not a Foreign Key to keep Tombstones)
+    deleted             BOOLEAN         NOT NULL DEFAULT 0                          -- Profile Removed?
+);
+
+-- Every change to a public profile replaces the previous revision of that user with a newer one,
+-- this keeps a single row per user while allowing clients to fetch everything since their last sync
+CREATE TRIGGER IF NOT EXISTS trg_user_revision_insert AFTER INSERT ON user
+BEGIN
+    INSERT OR REPLACE INTO user_revision (user_id, deleted) VALUES (NEW.id, NEW.deleted_at IS NOT NULL);
+    UPDATE user SET revision = last_insert_rowid() WHERE id = NEW.id;
+END;
+
+CREATE TRIGGER IF NOT EXISTS trg_user_revision_update AFTER UPDATE OF
+    username, displayname, subtitle, biography, avatar_hash, banner_hash,
+    accent_banner, accent_border, accent_background, deleted_at
+ON user
+WHEN
+    OLD.username IS NOT NEW.username OR OLD.displayname IS NOT NEW.displayname OR
+    OLD.subtitle IS NOT NEW.subtitle OR OLD.biography IS NOT NEW.biography OR
+    OLD.avatar_hash IS NOT NEW.avatar_hash OR OLD.banner_hash IS NOT NEW.banner_hash OR
+    OLD.accent_banner IS NOT NEW.accent_banner OR OLD.accent_border IS NOT NEW.accent_border OR
+    OLD.accent_background IS NOT NEW.accent_background OR OLD.deleted_at IS NOT NEW.deleted_at
+BEGIN
+    INSERT OR REPLACE INTO user_revision (user_id, deleted) VALUES (NEW.id, NEW.deleted_at IS NOT NULL);
+    UPDATE user SET revision = last_insert_rowid() WHERE id = NEW.id;
+END;
+
+CREATE TRIGGER IF NOT EXISTS trg_user_revision_delete AFTER DELETE ON user
+BEGIN
+    INSERT OR REPLACE INTO user_revision (user_id, deleted) VALUES (OLD.id, 1);
+END;
+
+CREATE TABLE IF NOT EXISTS user_challenge (
+    id                  INTEGER         NOT NULL PRIMARY KEY,                       -- Challenge ID
+    created             TIMESTAMP       NOT NULL DEFAULT CURRENT_TIMESTAMP,         -- Created At
+    expires             TIMESTAMP       NOT NULL,                                   -- Expires At
+    user_id             INTEGER         NOT NULL,                                   -- Relevant User ID
+    purpose             TEXT            NOT NULL,                                   -- Challenge Purpose (e.g. Escalation)
+    secret_hash         TEXT            NOT NULL,                                   -- Hashed Passcode or Token
+    data                TEXT            NOT NULL DEFAULT '',                        -- Arbitrary Data (e.g. IP Address)
+    attempts            INT             NOT NULL DEFAULT 0,                         -- Incorrect Attempts
+    UNIQUE (user_id, purpose),
+    FOREIGN KEY (user_id) REFERENCES user (id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_challenge_secret ON user_challenge (purpose, secret_hash);
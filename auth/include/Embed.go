@@ -8,5 +8,11 @@ var Templates embed.FS
 //go:embed DatabaseSchema.sql
 var DatabaseSchema string
 
+//go:embed PasswordDictionary.txt
+var PasswordDictionary string
+
+//go:embed DisposableDomains.txt
+var DisposableDomains string
+
 //go:embed DatabaseGeolocate.kani.gz
 var DatabaseGeolocate []byte
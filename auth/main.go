@@ -25,6 +25,14 @@ func main() {
 			core.DebugDatabaseUpdateGeolocation()
 			return
 		}
+		if strings.EqualFold(str, "debug_password_update_breached") {
+			core.DebugPasswordUpdateBreached()
+			return
+		}
+		if strings.EqualFold(str, "debug_email_update_disposable") {
+			core.DebugEmailUpdateDisposable()
+			return
+		}
 		if strings.EqualFold(str, "debug_email_render_templates") {
 			core.DebugEmailRenderTemplates()
 			return
@@ -33,27 +41,34 @@ func main() {
 			core.DebugImageResizer()
 			return
 		}
+		if strings.EqualFold(str, "debug_user_permissions") {
+			core.DebugUserPermissions()
+			return
+		}
 	}
 
 	// Startup Services
 	// 	Logger are unique and must be started specifically,
-	// 	everything else can be started at the same time
+	// 	everything else in the same stage can be started at the same time
+	// 	while later stages may depend on services from earlier stages
 	var stopCtx, stop = context.WithCancel(context.Background())
 	var stopWg sync.WaitGroup
 	var syncWg sync.WaitGroup
 
 	tools.LoggerMain.Log(tools.INFO, "Starting Services")
-	for _, fn := range []func(stop context.Context, await *sync.WaitGroup){
-		tools.GeolocateSetup,
-		tools.DatabaseSetup,
+	for _, stage := range [][]func(stop context.Context, await *sync.WaitGroup){
+		{tools.GeolocateSetup, tools.BreachedSetup, tools.ProofSetup, tools.DatabaseSetup},
+		{tools.TokenSetup, tools.CleanupSetup},
 	} {
-		syncWg.Add(1)
-		go func() {
-			defer syncWg.Done()
-			fn(stopCtx, &stopWg)
-		}()
+		for _, fn := range stage {
+			syncWg.Add(1)
+			go func() {
+				defer syncWg.Done()
+				fn(stopCtx, &stopWg)
+			}()
+		}
+		syncWg.Wait()
 	}
-	syncWg.Wait()
 	go StartupHTTP(stopCtx, &stopWg)
 
 	// Await Shutdown Signal
@@ -0,0 +1,60 @@
+package routes
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"dsoob/backend/tools"
+)
+
+func DELETE_Admin_Users_ID_Banner(w http.ResponseWriter, r *http.Request) {
+
+	session := tools.GetSession(r)
+	ok, userID := tools.GetSnowflake(w, r)
+	if !ok {
+		return
+	}
+
+	var Body struct {
+		Reason string `json:"reason" validate:"required,description"`
+	}
+	if !tools.BindJSON(w, r, &Body) {
+		return
+	}
+
+	// Fetch User
+	var UserEmailAddress string
+	err := tools.Database.QueryRowContext(r.Context(),
+		"SELECT email_address FROM user WHERE id = ?",
+		userID,
+	).Scan(
+		&UserEmailAddress,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		tools.SendClientError(w, r, tools.ERROR_UNKNOWN_USER)
+		return
+	}
+	if err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	}
+
+	// Delete Banner
+	if !tools.EndpointImageDelete(w, r, userID, "banner_hash", tools.ImageOptionsBanners) {
+		return
+	}
+	tools.LoggerAdmin.Data(tools.INFO, "User Banner Deleted", map[string]any{
+		"admin_id": session.UserID,
+		"user_id":  userID,
+		"reason":   Body.Reason,
+	})
+
+	// Notify User
+	go tools.EmailNotifyUserDeleted(UserEmailAddress,
+		tools.LocalsNotifyUserDeleted{
+			Content: "banner",
+			Reason:  Body.Reason,
+		},
+	)
+}
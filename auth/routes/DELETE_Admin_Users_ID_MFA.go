@@ -0,0 +1,57 @@
+package routes
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"dsoob/backend/tools"
+)
+
+func DELETE_Admin_Users_ID_MFA(w http.ResponseWriter, r *http.Request) {
+
+	session := tools.GetSession(r)
+	ok, userID := tools.GetSnowflake(w, r)
+	if !ok {
+		return
+	}
+
+	// Reset Fields
+	var UserEmailAddress string
+	err := tools.Database.QueryRowContext(r.Context(),
+		`UPDATE user SET
+			updated 		= CURRENT_TIMESTAMP,
+			mfa_enabled 	= false,
+			mfa_secret	 	= NULL,
+			mfa_codes 		= '',
+			mfa_codes_used 	= 0
+		WHERE mfa_enabled = TRUE AND id = ?
+		RETURNING email_address`,
+		userID,
+	).Scan(
+		&UserEmailAddress,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		tools.SendClientError(w, r, tools.ERROR_MFA_DISABLED)
+		return
+	}
+	if err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	}
+	tools.RecordUserEvent(r, userID, tools.EVENT_MFA_DISABLED, "admin")
+	tools.LoggerAdmin.Data(tools.INFO, "User MFA Removed", map[string]any{
+		"admin_id": session.UserID,
+		"user_id":  userID,
+	})
+
+	// Notify User
+	go tools.EmailNotifyUserDeleted(UserEmailAddress,
+		tools.LocalsNotifyUserDeleted{
+			Content: "two-factor authentication",
+			Reason:  "Removed by an Administrator",
+		},
+	)
+
+	w.WriteHeader(http.StatusNoContent)
+}
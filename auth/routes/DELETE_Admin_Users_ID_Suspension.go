@@ -0,0 +1,43 @@
+package routes
+
+import (
+	"net/http"
+
+	"dsoob/backend/tools"
+)
+
+func DELETE_Admin_Users_ID_Suspension(w http.ResponseWriter, r *http.Request) {
+
+	session := tools.GetSession(r)
+	ok, userID := tools.GetSnowflake(w, r)
+	if !ok {
+		return
+	}
+
+	// Unsuspend User
+	tag, err := tools.Database.ExecContext(r.Context(),
+		`UPDATE user SET
+			updated          = CURRENT_TIMESTAMP,
+			suspended_at     = NULL,
+			suspended_reason = NULL
+		WHERE id = ? AND suspended_at IS NOT NULL`,
+		userID,
+	)
+	if err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	}
+	if c, err := tag.RowsAffected(); err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	} else if c == 0 {
+		tools.SendClientError(w, r, tools.ERROR_UNKNOWN_USER)
+		return
+	}
+	tools.LoggerAdmin.Data(tools.INFO, "User Unsuspended", map[string]any{
+		"admin_id": session.UserID,
+		"user_id":  userID,
+	})
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,61 @@
+package routes
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"dsoob/backend/tools"
+)
+
+func DELETE_Auth_AccountRecovery(w http.ResponseWriter, r *http.Request) {
+
+	var Body struct {
+		Token string `json:"token" validate:"required,token"`
+	}
+	if !tools.BindJSON(w, r, &Body) {
+		return
+	}
+
+	tx, err := tools.Database.BeginTx(r.Context(), nil)
+	if err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	}
+	defer tx.Rollback()
+
+	// Cancel MFA Removal
+	// 	The reset token used to request the removal is revoked as well
+	var UserID int64
+	err = tx.QueryRowContext(r.Context(),
+		`UPDATE user SET
+			updated 		  = CURRENT_TIMESTAMP,
+			mfa_removal_at 	  = NULL,
+			token_mfa_removal = NULL
+		WHERE token_mfa_removal = ? AND mfa_removal_at IS NOT NULL
+		RETURNING id`,
+		Body.Token,
+	).Scan(
+		&UserID,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		tools.SendClientError(w, r, tools.ERROR_UNKNOWN_USER)
+		return
+	}
+	if err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	}
+	if err := tools.ChallengeDelete(r.Context(), tx, UserID, tools.CHALLENGE_PASSWORD_RESET); err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	}
+	tools.RecordUserEvent(r, UserID, tools.EVENT_MFA_REMOVAL_CANCELLED, "")
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -3,7 +3,9 @@ package routes
 import (
 	"database/sql"
 	"errors"
+	"fmt"
 	"net/http"
+	"time"
 
 	"dsoob/backend/tools"
 )
@@ -11,26 +13,47 @@ import (
 func DELETE_Users_Me(w http.ResponseWriter, r *http.Request) {
 
 	session := tools.GetSession(r)
-	if !session.Elevated {
-		tools.SendClientError(w, r, tools.ERROR_MFA_ESCALATION_REQUIRED)
+	if !tools.UseElevation(w, r, tools.ELEVATION_ACCOUNT_DELETE) {
 		return
 	}
 
-	// Delete Relevant Account
+	tx, err := tools.Database.BeginTx(r.Context(), nil)
+	if err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	}
+	defer tx.Rollback()
+
+	// Consume Elevation
+	// 	High-Risk actions require a fresh elevation every time
+	if err := tools.ElevationConsume(r.Context(), tx, session.SessionID); err != nil {
+		if errors.Is(err, tools.ErrElevationConsumed) {
+			tools.SendClientError(w, r, tools.ERROR_MFA_ESCALATION_REQUIRED)
+			return
+		}
+		tools.SendServerError(w, r, err)
+		return
+	}
+
+	// Schedule Account Deletion
+	// 	The account is only deleted once the grace period is over, giving the
+	// 	owner a chance to restore it should someone else have requested this
 	var (
-		UserID           int64
 		UserEmailAddress string
-		UserAvatarHash   *string
-		UserBannerHash   *string
+		RestoreToken     = tools.GenerateTokenString()
 	)
-	err := tools.Database.QueryRowContext(r.Context(),
-		"DELETE FROM user WHERE id = ? RETURNING id, email_address, avatar_hash, banner_hash",
+	err = tx.QueryRowContext(r.Context(),
+		`UPDATE user SET
+			updated       = CURRENT_TIMESTAMP,
+			deleted_at    = ?,
+			token_restore = ?
+		WHERE id = ? AND deleted_at IS NULL
+		RETURNING email_address`,
+		time.Now(),
+		RestoreToken,
 		session.UserID,
 	).Scan(
-		&UserID,
 		&UserEmailAddress,
-		&UserAvatarHash,
-		&UserBannerHash,
 	)
 	if errors.Is(err, sql.ErrNoRows) {
 		tools.SendClientError(w, r, tools.ERROR_UNKNOWN_USER)
@@ -41,31 +64,24 @@ func DELETE_Users_Me(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Delete Any Account Images
-	var imagePaths = []string{}
-	if UserAvatarHash != nil {
-		imagePaths = append(imagePaths,
-			tools.ImagePaths(tools.ImageOptionsAvatars, UserID, *UserAvatarHash)...,
-		)
+	// Logout User
+	SessionIDs, err := tools.DeleteUserSessions(r.Context(), tx, session.UserID, 0)
+	if err != nil {
+		tools.SendServerError(w, r, err)
+		return
 	}
-	if UserBannerHash != nil {
-		imagePaths = append(imagePaths,
-			tools.ImagePaths(tools.ImageOptionsBanners, UserID, *UserBannerHash)...,
-		)
+
+	if err := tx.Commit(); err != nil {
+		tools.SendServerError(w, r, err)
+		return
 	}
-	go func() {
-		if err := tools.StoragePublicDelete(imagePaths...); err != nil {
-			tools.LoggerStorage.Data(tools.ERROR, "Failed to Delete Account Images", map[string]any{
-				"paths": imagePaths,
-				"error": err.Error(),
-			})
-		}
-	}()
+	tools.RevokeAccessTokens(SessionIDs...)
 
 	// Notify User
-	go tools.EmailNotifyUserDeleted(UserEmailAddress,
-		tools.LocalsNotifyUserDeleted{
-			Reason: "User Request",
+	go tools.EmailNotifyUserDeletionPending(UserEmailAddress,
+		tools.LocalsNotifyUserDeletionPending{
+			Token:    RestoreToken,
+			Lifetime: fmt.Sprint(tools.DELETE_GRACE_DAYS),
 		},
 	)
 
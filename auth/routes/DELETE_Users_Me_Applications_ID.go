@@ -0,0 +1,36 @@
+package routes
+
+import (
+	"net/http"
+
+	"dsoob/backend/tools"
+)
+
+func DELETE_Users_Me_Applications_ID(w http.ResponseWriter, r *http.Request) {
+
+	session := tools.GetSession(r)
+	ok, applicationID := tools.GetSnowflake(w, r)
+	if !ok {
+		return
+	}
+
+	// Delete Relevant Application
+	tag, err := tools.Database.ExecContext(r.Context(),
+		"DELETE FROM application WHERE id = ? AND owner_id = ?",
+		applicationID,
+		session.UserID,
+	)
+	if err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	}
+	if c, err := tag.RowsAffected(); err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	} else if c == 0 {
+		tools.SendClientError(w, r, tools.ERROR_UNKNOWN_APPLICATION)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -6,5 +6,5 @@ import (
 )
 
 func DELETE_Users_Me_Avatar(w http.ResponseWriter, r *http.Request) {
-	tools.EndpointImageDelete(w, r, "avatar_hash", tools.ImageOptionsAvatars)
+	tools.EndpointImageDelete(w, r, tools.GetSession(r).UserID, "avatar_hash", tools.ImageOptionsAvatars)
 }
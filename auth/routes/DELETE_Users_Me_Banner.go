@@ -6,5 +6,5 @@ import (
 )
 
 func DELETE_Users_Me_Banner(w http.ResponseWriter, r *http.Request) {
-	tools.EndpointImageDelete(w, r, "banner_hash", tools.ImageOptionsBanners)
+	tools.EndpointImageDelete(w, r, tools.GetSession(r).UserID, "banner_hash", tools.ImageOptionsBanners)
 }
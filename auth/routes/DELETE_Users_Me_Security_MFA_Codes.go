@@ -10,8 +10,7 @@ import (
 func DELETE_Users_Me_Security_MFA_Codes(w http.ResponseWriter, r *http.Request) {
 
 	session := tools.GetSession(r)
-	if !session.Elevated {
-		tools.SendClientError(w, r, tools.ERROR_MFA_ESCALATION_REQUIRED)
+	if !tools.UseElevation(w, r, tools.ELEVATION_MFA) {
 		return
 	}
 
@@ -38,6 +37,7 @@ func DELETE_Users_Me_Security_MFA_Codes(w http.ResponseWriter, r *http.Request)
 		tools.SendClientError(w, r, tools.ERROR_MFA_DISABLED)
 		return
 	}
+	tools.RecordUserEvent(r, session.UserID, tools.EVENT_MFA_RECOVERY_REGENERATED, "")
 
 	// Return Results
 	tools.SendJSON(w, r, http.StatusOK, map[string]any{
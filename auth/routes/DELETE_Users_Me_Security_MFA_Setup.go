@@ -9,8 +9,7 @@ import (
 func DELETE_Users_Me_Security_MFA_Setup(w http.ResponseWriter, r *http.Request) {
 
 	session := tools.GetSession(r)
-	if !session.Elevated {
-		tools.SendClientError(w, r, tools.ERROR_MFA_ESCALATION_REQUIRED)
+	if !tools.UseElevation(w, r, tools.ELEVATION_MFA) {
 		return
 	}
 
@@ -36,6 +35,7 @@ func DELETE_Users_Me_Security_MFA_Setup(w http.ResponseWriter, r *http.Request)
 		tools.SendClientError(w, r, tools.ERROR_MFA_DISABLED)
 		return
 	}
+	tools.RecordUserEvent(r, session.UserID, tools.EVENT_MFA_DISABLED, "user")
 
 	w.WriteHeader(http.StatusNoContent)
 }
@@ -0,0 +1,41 @@
+package routes
+
+import (
+	"net/http"
+
+	"dsoob/backend/tools"
+)
+
+func DELETE_Users_Me_Security_Sessions(w http.ResponseWriter, r *http.Request) {
+
+	session := tools.GetSession(r)
+	if !tools.UseElevation(w, r, tools.ELEVATION_SESSIONS) {
+		return
+	}
+
+	tx, err := tools.Database.BeginTx(r.Context(), nil)
+	if err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	}
+	defer tx.Rollback()
+
+	// Delete Other Sessions
+	SessionIDs, err := tools.DeleteUserSessions(r.Context(), tx, session.UserID, session.SessionID)
+	if err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	}
+	tools.RevokeAccessTokens(SessionIDs...)
+	tools.RecordUserEvent(r, session.UserID, tools.EVENT_SESSION_REVOKED, "others")
+
+	// Return Results
+	tools.SendJSON(w, r, http.StatusOK, map[string]any{
+		"revoked": SessionIDs,
+	})
+}
@@ -2,6 +2,7 @@ package routes
 
 import (
 	"net/http"
+	"strconv"
 
 	"dsoob/backend/tools"
 )
@@ -9,8 +10,7 @@ import (
 func DELETE_Users_Me_Security_Sessions_ID(w http.ResponseWriter, r *http.Request) {
 
 	session := tools.GetSession(r)
-	if !session.Elevated {
-		tools.SendClientError(w, r, tools.ERROR_MFA_ESCALATION_REQUIRED)
+	if !tools.UseElevation(w, r, tools.ELEVATION_SESSIONS) {
 		return
 	}
 	ok, snowflake := tools.GetSnowflake(w, r)
@@ -20,8 +20,9 @@ func DELETE_Users_Me_Security_Sessions_ID(w http.ResponseWriter, r *http.Request
 
 	// Delete Relevant Session
 	tag, err := tools.Database.ExecContext(r.Context(),
-		"DELETE FROM user_session WHERE id = ?",
+		"DELETE FROM user_session WHERE id = ? AND user_id = ?",
 		snowflake,
+		session.UserID,
 	)
 	if err != nil {
 		tools.SendServerError(w, r, err)
@@ -35,6 +36,8 @@ func DELETE_Users_Me_Security_Sessions_ID(w http.ResponseWriter, r *http.Request
 		tools.SendClientError(w, r, tools.ERROR_UNKNOWN_SESSION)
 		return
 	}
+	tools.RevokeAccessTokens(snowflake)
+	tools.RecordUserEvent(r, session.UserID, tools.EVENT_SESSION_REVOKED, strconv.FormatInt(snowflake, 10))
 
 	w.WriteHeader(http.StatusNoContent)
 }
@@ -0,0 +1,36 @@
+package routes
+
+import (
+	"net/http"
+
+	"dsoob/backend/tools"
+)
+
+func DELETE_Users_Me_Security_Tokens_ID(w http.ResponseWriter, r *http.Request) {
+
+	session := tools.GetSession(r)
+	ok, tokenID := tools.GetSnowflake(w, r)
+	if !ok {
+		return
+	}
+
+	// Revoke Relevant Token
+	tag, err := tools.Database.ExecContext(r.Context(),
+		"DELETE FROM user_token WHERE id = ? AND user_id = ?",
+		tokenID,
+		session.UserID,
+	)
+	if err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	}
+	if c, err := tag.RowsAffected(); err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	} else if c == 0 {
+		tools.SendClientError(w, r, tools.ERROR_UNKNOWN_TOKEN)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,28 @@
+package routes
+
+import (
+	"dsoob/backend/tools"
+	"net/http"
+)
+
+func DELETE_Users_Me_Settings(w http.ResponseWriter, r *http.Request) {
+	session := tools.GetSession(r)
+	ok, namespace := tools.GetSettingsNamespace(w, r)
+	if !ok {
+		return
+	}
+
+	// Delete Settings
+	// 	History is deleted alongside the settings to free up the namespace
+	err := tools.SettingsDelete(session.UserID, namespace)
+	if err == tools.ErrStorageFileNotFound {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	if err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,84 @@
+package routes
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"dsoob/backend/tools"
+)
+
+func GET_Admin_Users(w http.ResponseWriter, r *http.Request) {
+
+	// Parse Query
+	// 	Matches a user by their exact ID or by any part of their username or email address
+	var (
+		Query        = strings.TrimSpace(r.URL.Query().Get("query"))
+		QueryID, _   = strconv.ParseInt(Query, 10, 64)
+		QueryPattern = "%" + strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(Query) + "%"
+	)
+	if Query == "" || len(Query) > 256 {
+		tools.SendClientError(w, r, tools.ERROR_BODY_INVALID_FIELD)
+		return
+	}
+
+	// Search Users
+	rows, err := tools.Database.QueryContext(r.Context(),
+		`SELECT
+			id, created, email_address, email_verified, mfa_enabled,
+			username, displayname, permissions, suspended_at, suspended_reason, deleted_at
+		FROM user
+		WHERE id = ? OR username LIKE ? ESCAPE '\' OR email_address LIKE ? ESCAPE '\'
+		ORDER BY id LIMIT 50`,
+		QueryID,
+		QueryPattern,
+		QueryPattern,
+	)
+	if err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	}
+	defer rows.Close()
+
+	// Organize Users
+	var (
+		UserItems           = make([]map[string]any, 0, 1)
+		UserID              int64
+		UserCreated         time.Time
+		UserEmailAddress    string
+		UserEmailVerified   bool
+		UserMFAEnabled      bool
+		UserName            string
+		UserDisplayname     string
+		UserPermissions     int64
+		UserSuspendedAt     *time.Time
+		UserSuspendedReason *string
+		UserDeletedAt       *time.Time
+	)
+	for rows.Next() {
+		if err := rows.Scan(
+			&UserID, &UserCreated, &UserEmailAddress, &UserEmailVerified, &UserMFAEnabled,
+			&UserName, &UserDisplayname, &UserPermissions, &UserSuspendedAt, &UserSuspendedReason, &UserDeletedAt,
+		); err != nil {
+			tools.SendServerError(w, r, err)
+			return
+		}
+		UserItems = append(UserItems, map[string]any{
+			"id":               UserID,
+			"created":          UserCreated,
+			"email_address":    UserEmailAddress,
+			"email_verified":   UserEmailVerified,
+			"mfa_enabled":      UserMFAEnabled,
+			"username":         UserName,
+			"displayname":      UserDisplayname,
+			"permissions":      UserPermissions,
+			"suspended_at":     UserSuspendedAt,
+			"suspended_reason": UserSuspendedReason,
+			"deleted_at":       UserDeletedAt,
+		})
+	}
+
+	// Return Results
+	tools.SendJSON(w, r, http.StatusOK, UserItems)
+}
@@ -0,0 +1,61 @@
+package routes
+
+import (
+	"net/http"
+	"time"
+
+	"dsoob/backend/tools"
+)
+
+func GET_Admin_Users_ID_Sessions(w http.ResponseWriter, r *http.Request) {
+
+	ok, userID := tools.GetSnowflake(w, r)
+	if !ok {
+		return
+	}
+
+	// Fetch Sessions
+	rows, err := tools.Database.QueryContext(r.Context(),
+		`SELECT id, created, updated, device_ip_address, device_user_agent
+		FROM user_session WHERE user_id = ? ORDER BY updated DESC`,
+		userID,
+	)
+	if err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	}
+	defer rows.Close()
+
+	// Organize Sessions
+	var (
+		LoginItems           = make([]map[string]any, 0, 1)
+		LoginID              int64
+		LoginCreated         time.Time
+		LoginUpdated         time.Time
+		LoginDeviceIPAddress string
+		LoginDeviceUserAgent string
+	)
+	for rows.Next() {
+		if err := rows.Scan(
+			&LoginID,
+			&LoginCreated,
+			&LoginUpdated,
+			&LoginDeviceIPAddress,
+			&LoginDeviceUserAgent,
+		); err != nil {
+			tools.SendServerError(w, r, err)
+			return
+		}
+		LoginItems = append(LoginItems, map[string]any{
+			"id":         LoginID,
+			"created":    LoginCreated,
+			"updated":    LoginUpdated,
+			"ip_address": LoginDeviceIPAddress,
+			"location":   tools.LookupLocation(LoginDeviceIPAddress),
+			"browser":    tools.LookupBrowser(LoginDeviceUserAgent),
+		})
+	}
+
+	// Return Results
+	tools.SendJSON(w, r, http.StatusOK, LoginItems)
+}
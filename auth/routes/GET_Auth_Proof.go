@@ -0,0 +1,30 @@
+package routes
+
+import (
+	"net/http"
+	"time"
+
+	"dsoob/backend/tools"
+)
+
+func GET_Auth_Proof(w http.ResponseWriter, r *http.Request) {
+
+	// Proof of Work Disabled?
+	if tools.POW_DIFFICULTY <= 0 {
+		tools.SendJSON(w, r, http.StatusOK, map[string]any{
+			"required": false,
+		})
+		return
+	}
+
+	// Issue Challenge
+	challenge, claims := tools.ProofIssue(tools.GetRemoteIP(r))
+
+	// Return Results
+	tools.SendJSON(w, r, http.StatusOK, map[string]any{
+		"required":   true,
+		"challenge":  challenge,
+		"difficulty": claims.Difficulty,
+		"expires":    time.Unix(claims.ExpiresAt, 0),
+	})
+}
@@ -0,0 +1,55 @@
+package routes
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"dsoob/backend/tools"
+)
+
+func GET_Exports_Token(w http.ResponseWriter, r *http.Request) {
+
+	// Fetch Export
+	var (
+		ExportID     int64
+		ExportUserID int64
+	)
+	err := tools.Database.QueryRowContext(r.Context(),
+		"SELECT id, user_id FROM user_export WHERE token = ? AND expires > ?",
+		r.PathValue("token"),
+		time.Now(),
+	).Scan(
+		&ExportID,
+		&ExportUserID,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		tools.SendClientError(w, r, tools.ERROR_UNKNOWN_EXPORT)
+		return
+	}
+	if err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	}
+
+	// Fetch File
+	// 	Exports are small enough that reading them into memory is fine
+	raw, err := tools.StoragePrivateRead(tools.ExportPath(ExportUserID, ExportID))
+	if errors.Is(err, tools.ErrStorageFileNotFound) {
+		tools.SendClientError(w, r, tools.ERROR_UNKNOWN_EXPORT)
+		return
+	}
+	if err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	}
+
+	// Return Results
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Length", strconv.Itoa(len(raw)))
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s-%d.zip"`, tools.SITE_NAME, ExportUserID))
+	w.Write(raw)
+}
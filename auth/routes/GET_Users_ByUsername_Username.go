@@ -0,0 +1,29 @@
+package routes
+
+import (
+	"dsoob/backend/tools"
+	"net/http"
+)
+
+func GET_Users_ByUsername_Username(w http.ResponseWriter, r *http.Request) {
+
+	username := r.PathValue("username")
+	if !tools.REGEX_USERNAME.MatchString(username) {
+		tools.SendClientError(w, r, tools.ERROR_BODY_INVALID_FIELD)
+		return
+	}
+
+	// Fetch User
+	profiles, err := tools.ProfileQuery(r.Context(), "username = ? COLLATE NOCASE", username)
+	if err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	}
+	if len(profiles) == 0 {
+		tools.SendClientError(w, r, tools.ERROR_UNKNOWN_USER)
+		return
+	}
+
+	// Return Results
+	tools.SendProfile(w, r, profiles[0])
+}
@@ -0,0 +1,102 @@
+package routes
+
+import (
+	"net/http"
+	"strconv"
+
+	"dsoob/backend/tools"
+)
+
+func GET_Users_Changes(w http.ResponseWriter, r *http.Request) {
+
+	// Parse Pagination
+	// 	Changes are returned oldest first, use the returned revision as 'since' to fetch the next page
+	var (
+		QuerySince int64 = 0
+		QueryLimit int64 = 100
+	)
+	if v := r.URL.Query().Get("since"); v != "" {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || n < 0 {
+			tools.SendClientError(w, r, tools.ERROR_BODY_INVALID_FIELD)
+			return
+		}
+		QuerySince = n
+	}
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || n < 1 || n > 100 {
+			tools.SendClientError(w, r, tools.ERROR_BODY_INVALID_FIELD)
+			return
+		}
+		QueryLimit = n
+	}
+
+	// Fetch Revisions
+	// 	One more than requested is fetched to learn whether another page exists
+	rows, err := tools.Database.QueryContext(r.Context(),
+		"SELECT revision, user_id, deleted FROM user_revision WHERE revision > ? ORDER BY revision LIMIT ?",
+		QuerySince,
+		QueryLimit+1,
+	)
+	if err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	}
+	defer rows.Close()
+
+	var (
+		ChangeRevision = QuerySince
+		ChangeMore     = false
+		ChangeUsers    = make([]int64, 0, QueryLimit)
+		ChangeDeleted  = make([]int64, 0)
+		count          int64
+	)
+	for rows.Next() {
+		var (
+			RevisionID      int64
+			RevisionUserID  int64
+			RevisionDeleted bool
+		)
+		if err := rows.Scan(&RevisionID, &RevisionUserID, &RevisionDeleted); err != nil {
+			tools.SendServerError(w, r, err)
+			return
+		}
+		if count++; count > QueryLimit {
+			ChangeMore = true
+			break
+		}
+		ChangeRevision = RevisionID
+		if RevisionDeleted {
+			ChangeDeleted = append(ChangeDeleted, RevisionUserID)
+		} else {
+			ChangeUsers = append(ChangeUsers, RevisionUserID)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	}
+	rows.Close()
+
+	// Fetch Profiles
+	// 	Profiles removed since their revision was read are reported as deleted
+	UserItems := []tools.UserProfile{}
+	if len(ChangeUsers) > 0 {
+		var UserUnknown []int64
+		UserItems, UserUnknown, err = tools.ProfileQueryBulk(r.Context(), ChangeUsers)
+		if err != nil {
+			tools.SendServerError(w, r, err)
+			return
+		}
+		ChangeDeleted = append(ChangeDeleted, UserUnknown...)
+	}
+
+	// Return Results
+	tools.SendJSON(w, r, http.StatusOK, map[string]any{
+		"revision": ChangeRevision,
+		"more":     ChangeMore,
+		"users":    UserItems,
+		"deleted":  ChangeDeleted,
+	})
+}
@@ -0,0 +1,28 @@
+package routes
+
+import (
+	"dsoob/backend/tools"
+	"net/http"
+)
+
+func GET_Users_ID(w http.ResponseWriter, r *http.Request) {
+
+	ok, userID := tools.GetSnowflake(w, r)
+	if !ok {
+		return
+	}
+
+	// Fetch User
+	profiles, err := tools.ProfileQuery(r.Context(), "id = ?", userID)
+	if err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	}
+	if len(profiles) == 0 {
+		tools.SendClientError(w, r, tools.ERROR_UNKNOWN_USER)
+		return
+	}
+
+	// Return Results
+	tools.SendProfile(w, r, profiles[0])
+}
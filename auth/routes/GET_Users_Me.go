@@ -20,6 +20,7 @@ func GET_Users_Me(w http.ResponseWriter, r *http.Request) {
 		UserEmailAddress     string
 		UserEmailVerified    bool
 		UserMFAEnabled       bool
+		UserPermissions      int64
 		UserName             string
 		UserDisplayname      string
 		UserSubtitle         *string
@@ -32,14 +33,14 @@ func GET_Users_Me(w http.ResponseWriter, r *http.Request) {
 	)
 	err := tools.Database.QueryRowContext(r.Context(),
 		`SELECT
-			id, created, email_address, email_verified, mfa_enabled,
+			id, created, email_address, email_verified, mfa_enabled, permissions,
 			username, displayname, subtitle, biography,
 			avatar_hash, banner_hash,
 			accent_banner, accent_border, accent_background
 		FROM user WHERE id = ?`,
 		session.UserID,
 	).Scan(
-		&UserID, &UserCreated, &UserEmailAddress, &UserEmailVerified, &UserMFAEnabled,
+		&UserID, &UserCreated, &UserEmailAddress, &UserEmailVerified, &UserMFAEnabled, &UserPermissions,
 		&UserName, &UserDisplayname, &UserSubtitle, &UserBiography,
 		&UserAvatarHash, &UserBannerHash,
 		&UserAccentBanner, &UserAccentBorder, &UserAccentBackground,
@@ -53,13 +54,31 @@ func GET_Users_Me(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Fetch Pending Email
+	// 	Address awaiting confirmation from PATCH /users/@me/security/email
+	var UserEmailPending *string
+	err = tools.Database.QueryRowContext(r.Context(),
+		"SELECT data FROM user_challenge WHERE user_id = ? AND purpose = ? AND expires > ?",
+		session.UserID,
+		tools.CHALLENGE_EMAIL_CHANGE,
+		time.Now(),
+	).Scan(
+		&UserEmailPending,
+	)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		tools.SendServerError(w, r, err)
+		return
+	}
+
 	// Return Results
 	tools.SendJSON(w, r, http.StatusOK, map[string]any{
 		"id":                UserID,
 		"created":           UserCreated,
 		"email_address":     UserEmailAddress,
 		"email_verified":    UserEmailVerified,
+		"email_pending":     UserEmailPending,
 		"mfa_enabled":       UserMFAEnabled,
+		"permissions":       UserPermissions,
 		"username":          UserName,
 		"displayname":       UserDisplayname,
 		"subtitle":          UserSubtitle,
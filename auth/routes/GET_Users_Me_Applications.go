@@ -0,0 +1,58 @@
+package routes
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"dsoob/backend/tools"
+)
+
+func GET_Users_Me_Applications(w http.ResponseWriter, r *http.Request) {
+
+	session := tools.GetSession(r)
+
+	// Fetch Applications
+	rows, err := tools.Database.QueryContext(r.Context(),
+		"SELECT id, created, name, scopes FROM application WHERE owner_id = ? ORDER BY id",
+		session.UserID,
+	)
+	if err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	}
+	defer rows.Close()
+
+	// Organize Applications
+	var (
+		ApplicationItems     = make([]map[string]any, 0, tools.APPLICATION_LIMIT)
+		ApplicationID        int64
+		ApplicationCreated   time.Time
+		ApplicationName      string
+		ApplicationScopesRAW string
+	)
+	for rows.Next() {
+		if err := rows.Scan(
+			&ApplicationID,
+			&ApplicationCreated,
+			&ApplicationName,
+			&ApplicationScopesRAW,
+		); err != nil {
+			tools.SendServerError(w, r, err)
+			return
+		}
+		ApplicationScopes := []string{}
+		if ApplicationScopesRAW != "" {
+			ApplicationScopes = strings.Split(ApplicationScopesRAW, tools.ARRAY_DELIMITER)
+		}
+		ApplicationItems = append(ApplicationItems, map[string]any{
+			"id":      ApplicationID,
+			"created": ApplicationCreated,
+			"name":    ApplicationName,
+			"scopes":  ApplicationScopes,
+		})
+	}
+
+	// Return Results
+	tools.SendJSON(w, r, http.StatusOK, ApplicationItems)
+}
@@ -0,0 +1,60 @@
+package routes
+
+import (
+	"net/http"
+	"time"
+
+	"dsoob/backend/tools"
+)
+
+func GET_Users_Me_Invites(w http.ResponseWriter, r *http.Request) {
+
+	session := tools.GetSession(r)
+
+	// Fetch Invites
+	rows, err := tools.Database.QueryContext(r.Context(),
+		"SELECT id, created, expires, code, uses, uses_limit FROM user_invite WHERE user_id = ? ORDER BY id",
+		session.UserID,
+	)
+	if err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	}
+	defer rows.Close()
+
+	// Organize Invites
+	var (
+		InviteItems     = make([]map[string]any, 0, tools.INVITE_LIMIT)
+		InviteID        int64
+		InviteCreated   time.Time
+		InviteExpires   *time.Time
+		InviteCode      string
+		InviteUses      int
+		InviteUsesLimit int
+	)
+	for rows.Next() {
+		if err := rows.Scan(
+			&InviteID,
+			&InviteCreated,
+			&InviteExpires,
+			&InviteCode,
+			&InviteUses,
+			&InviteUsesLimit,
+		); err != nil {
+			tools.SendServerError(w, r, err)
+			return
+		}
+		InviteItems = append(InviteItems, map[string]any{
+			"id":         InviteID,
+			"created":    InviteCreated,
+			"expires":    InviteExpires,
+			"expired":    InviteExpires != nil && time.Now().After(*InviteExpires),
+			"code":       InviteCode,
+			"uses":       InviteUses,
+			"uses_limit": InviteUsesLimit,
+		})
+	}
+
+	// Return Results
+	tools.SendJSON(w, r, http.StatusOK, InviteItems)
+}
@@ -0,0 +1,89 @@
+package routes
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"dsoob/backend/tools"
+)
+
+func GET_Users_Me_Security_Events(w http.ResponseWriter, r *http.Request) {
+
+	session := tools.GetSession(r)
+
+	// Parse Pagination
+	// 	Events are returned newest first, use the last ID as 'before' to fetch the next page
+	var (
+		QueryBefore int64 = math.MaxInt64
+		QueryLimit  int64 = 50
+	)
+	if v := r.URL.Query().Get("before"); v != "" {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || n < 1 {
+			tools.SendClientError(w, r, tools.ERROR_BODY_INVALID_FIELD)
+			return
+		}
+		QueryBefore = n
+	}
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || n < 1 || n > 100 {
+			tools.SendClientError(w, r, tools.ERROR_BODY_INVALID_FIELD)
+			return
+		}
+		QueryLimit = n
+	}
+
+	// Fetch Events
+	rows, err := tools.Database.QueryContext(r.Context(),
+		`SELECT id, created, type, detail, ip_address, user_agent
+		FROM user_event WHERE user_id = ? AND id < ?
+		ORDER BY id DESC LIMIT ?`,
+		session.UserID,
+		QueryBefore,
+		QueryLimit,
+	)
+	if err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	}
+	defer rows.Close()
+
+	// Organize Events
+	var (
+		EventItems     = make([]map[string]any, 0, QueryLimit)
+		EventID        int64
+		EventCreated   time.Time
+		EventType      string
+		EventDetail    string
+		EventIPAddress string
+		EventUserAgent string
+	)
+	for rows.Next() {
+		if err := rows.Scan(
+			&EventID,
+			&EventCreated,
+			&EventType,
+			&EventDetail,
+			&EventIPAddress,
+			&EventUserAgent,
+		); err != nil {
+			tools.SendServerError(w, r, err)
+			return
+		}
+		EventItems = append(EventItems, map[string]any{
+			"id":         EventID,
+			"created":    EventCreated,
+			"type":       EventType,
+			"detail":     EventDetail,
+			"ip_address": EventIPAddress,
+			"location":   tools.LookupLocation(EventIPAddress),
+			"browser":    tools.LookupBrowser(EventUserAgent),
+		})
+	}
+
+	// Return Results
+	tools.SendJSON(w, r, http.StatusOK, EventItems)
+}
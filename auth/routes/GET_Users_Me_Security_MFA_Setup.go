@@ -11,8 +11,7 @@ import (
 func GET_Users_Me_Security_MFA_Setup(w http.ResponseWriter, r *http.Request) {
 
 	session := tools.GetSession(r)
-	if !session.Elevated {
-		tools.SendClientError(w, r, tools.ERROR_MFA_ESCALATION_REQUIRED)
+	if !tools.UseElevation(w, r, tools.ELEVATION_MFA) {
 		return
 	}
 
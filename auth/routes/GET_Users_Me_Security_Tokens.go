@@ -0,0 +1,62 @@
+package routes
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"dsoob/backend/tools"
+)
+
+func GET_Users_Me_Security_Tokens(w http.ResponseWriter, r *http.Request) {
+
+	session := tools.GetSession(r)
+
+	// Fetch Tokens
+	rows, err := tools.Database.QueryContext(r.Context(),
+		"SELECT id, created, expires, name, scopes FROM user_token WHERE user_id = ? ORDER BY id",
+		session.UserID,
+	)
+	if err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	}
+	defer rows.Close()
+
+	// Organize Tokens
+	var (
+		TokenItems     = make([]map[string]any, 0, tools.PERSONAL_TOKEN_LIMIT)
+		TokenID        int64
+		TokenCreated   time.Time
+		TokenExpires   *time.Time
+		TokenName      string
+		TokenScopesRAW string
+	)
+	for rows.Next() {
+		if err := rows.Scan(
+			&TokenID,
+			&TokenCreated,
+			&TokenExpires,
+			&TokenName,
+			&TokenScopesRAW,
+		); err != nil {
+			tools.SendServerError(w, r, err)
+			return
+		}
+		TokenScopes := []string{}
+		if TokenScopesRAW != "" {
+			TokenScopes = strings.Split(TokenScopesRAW, tools.ARRAY_DELIMITER)
+		}
+		TokenItems = append(TokenItems, map[string]any{
+			"id":      TokenID,
+			"created": TokenCreated,
+			"expires": TokenExpires,
+			"expired": TokenExpires != nil && time.Now().After(*TokenExpires),
+			"name":    TokenName,
+			"scopes":  TokenScopes,
+		})
+	}
+
+	// Return Results
+	tools.SendJSON(w, r, http.StatusOK, TokenItems)
+}
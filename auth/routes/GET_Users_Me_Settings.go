@@ -1,25 +1,21 @@
 package routes
 
 import (
-	"crypto/sha256"
 	"dsoob/backend/tools"
-	"fmt"
 	"net/http"
-	"os"
-	"path"
 	"strconv"
 )
 
 func GET_Users_Me_Settings(w http.ResponseWriter, r *http.Request) {
 	session := tools.GetSession(r)
+	ok, namespace := tools.GetSettingsNamespace(w, r)
+	if !ok {
+		return
+	}
 
 	// Fetch File
-	filepath := path.Join(
-		tools.DATA_DIRECTORY, "settings",
-		strconv.FormatInt(session.UserID, 10)+".raw",
-	)
-	raw, err := os.ReadFile(filepath)
-	if os.IsNotExist(err) {
+	raw, err := tools.SettingsRead(session.UserID, namespace)
+	if err == tools.ErrStorageFileNotFound {
 		w.WriteHeader(http.StatusNoContent)
 		return
 	}
@@ -29,7 +25,7 @@ func GET_Users_Me_Settings(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Check Client Cache
-	storedHash := fmt.Sprintf("%x", sha256.Sum256(raw))
+	storedHash := tools.SettingsHash(raw)
 	clientHash := r.Header.Get("If-None-Match")
 	if storedHash == clientHash {
 		w.WriteHeader(http.StatusNotModified)
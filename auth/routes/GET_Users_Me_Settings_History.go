@@ -0,0 +1,24 @@
+package routes
+
+import (
+	"dsoob/backend/tools"
+	"net/http"
+)
+
+func GET_Users_Me_Settings_History(w http.ResponseWriter, r *http.Request) {
+	session := tools.GetSession(r)
+	ok, namespace := tools.GetSettingsNamespace(w, r)
+	if !ok {
+		return
+	}
+
+	// Fetch History
+	versions, err := tools.SettingsHistory(session.UserID, namespace)
+	if err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	}
+
+	// Return Results
+	tools.SendJSON(w, r, http.StatusOK, versions)
+}
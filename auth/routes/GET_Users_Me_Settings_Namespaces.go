@@ -0,0 +1,40 @@
+package routes
+
+import (
+	"dsoob/backend/tools"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+func GET_Users_Me_Settings_Namespaces(w http.ResponseWriter, r *http.Request) {
+	session := tools.GetSession(r)
+
+	// Fetch Namespaces
+	namespaces, err := tools.SettingsList(session.UserID)
+	if err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	}
+
+	// Organize Namespaces
+	// 	Namespaces of other sessions are hidden and the current one is shown by its alias
+	var (
+		currentSession = "@" + strconv.FormatInt(session.SessionID, 10)
+		results        = make([]tools.SettingsNamespace, 0, len(namespaces))
+	)
+	for _, ns := range namespaces {
+		switch {
+		case ns.Name == "":
+			ns.Name = "default"
+		case ns.Name == currentSession && session.SessionID != 0:
+			ns.Name = "@session"
+		case strings.HasPrefix(ns.Name, "@"):
+			continue
+		}
+		results = append(results, ns)
+	}
+
+	// Return Results
+	tools.SendJSON(w, r, http.StatusOK, results)
+}
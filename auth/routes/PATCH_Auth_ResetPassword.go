@@ -4,6 +4,7 @@ import (
 	"database/sql"
 	"errors"
 	"net/http"
+	"slices"
 	"strings"
 
 	"dsoob/backend/tools"
@@ -13,26 +14,54 @@ func PATCH_Auth_ResetPassword(w http.ResponseWriter, r *http.Request) {
 
 	var Body struct {
 		NewPassword string `json:"password" validate:"required,password"`
+		Passcode    string `json:"passcode" validate:"omitempty,passcode"`
 		Token       string `json:"token" validate:"required,token"`
 	}
 	if !tools.BindJSON(w, r, &Body) {
 		return
 	}
+	if tools.PasswordBreached(Body.NewPassword) {
+		tools.SendClientError(w, r, tools.ERROR_LOGIN_PASSWORD_BREACHED)
+		return
+	}
+
+	// Fetch Challenge
+	challenge, err := tools.ChallengeLookup(r.Context(), tools.CHALLENGE_PASSWORD_RESET, Body.Token)
+	if err == tools.ErrChallengeNotFound {
+		tools.ProofFail(tools.GetRemoteIP(r))
+		tools.SendClientError(w, r, tools.ERROR_UNKNOWN_USER)
+		return
+	}
+	if err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	}
 
 	// Fetch User
 	var (
-		UserID                 int64
+		UserID                 = challenge.UserID
 		UserEmailAddress       string
+		UserName               string
 		UserPasswordHistoryRAW string
+		UserMFAEnabled         bool
+		UserMFASecret          *string
+		UserMFACodesRAW        string
+		UserMFACodesUsed       int
 	)
-	err := tools.Database.QueryRowContext(r.Context(),
-		`SELECT id, email_address, password_history
-		FROM user WHERE token_reset = ? AND token_reset_eat > CURRENT_TIMESTAMP`,
-		Body.Token,
+	err = tools.Database.QueryRowContext(r.Context(),
+		`SELECT
+			email_address, username, password_history,
+			mfa_enabled, mfa_secret, mfa_codes, mfa_codes_used
+		FROM user WHERE id = ?`,
+		UserID,
 	).Scan(
-		&UserID,
 		&UserEmailAddress,
+		&UserName,
 		&UserPasswordHistoryRAW,
+		&UserMFAEnabled,
+		&UserMFASecret,
+		&UserMFACodesRAW,
+		&UserMFACodesUsed,
 	)
 	if errors.Is(err, sql.ErrNoRows) {
 		tools.SendClientError(w, r, tools.ERROR_UNKNOWN_USER)
@@ -42,9 +71,34 @@ func PATCH_Auth_ResetPassword(w http.ResponseWriter, r *http.Request) {
 		tools.SendServerError(w, r, err)
 		return
 	}
+	if !tools.ValidatePasswordStrength(w, r, Body.NewPassword, UserEmailAddress, UserName) {
+		return
+	}
+
+	// Filter: Multi-Factor Authentication
+	// 	Access to the email address alone must not be enough to take over the account,
+	// 	users who lost their authenticator and recovery codes must use the account recovery
+	if UserMFAEnabled && UserMFASecret != nil {
+		if ok, method := tools.ValidateMFA(w, r, UserID, *UserMFASecret, UserMFACodesRAW, UserMFACodesUsed, Body.Passcode); !ok {
+			if method != "" {
+				tools.RecordUserEvent(r, UserID, tools.EVENT_LOGIN_FAILED, method)
+				tools.ProofFail(tools.GetRemoteIP(r))
+				if err := tools.ChallengeFail(r.Context(), challenge.ID); err != nil {
+					tools.LoggerDatabase.Data(tools.ERROR, "Cannot Count Challenge Attempt", map[string]any{
+						"challenge_id": challenge.ID,
+						"error":        err.Error(),
+					})
+				}
+			}
+			return
+		}
+	}
 
 	// Update Password History
-	UserPasswordHistory := strings.Split(UserPasswordHistoryRAW, tools.ARRAY_DELIMITER)
+	UserPasswordHistory := slices.DeleteFunc(
+		strings.Split(UserPasswordHistoryRAW, tools.ARRAY_DELIMITER),
+		func(h string) bool { return h == "" },
+	)
 	for _, oldPassword := range UserPasswordHistory {
 		if ok, err := tools.ComparePasswordHash(oldPassword, Body.NewPassword); err != nil {
 			tools.SendServerError(w, r, err)
@@ -64,12 +118,26 @@ func PATCH_Auth_ResetPassword(w http.ResponseWriter, r *http.Request) {
 		UserPasswordHistory = UserPasswordHistory[1:]
 	}
 
+	tx, err := tools.Database.BeginTx(r.Context(), nil)
+	if err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	}
+	defer tx.Rollback()
+
 	// Update User
-	tag, err := tools.Database.ExecContext(r.Context(),
+	err = tools.ChallengeConsume(r.Context(), tx, challenge.ID)
+	if err == tools.ErrChallengeNotFound {
+		tools.SendClientError(w, r, tools.ERROR_UNKNOWN_USER)
+		return
+	}
+	if err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	}
+	tag, err := tx.ExecContext(r.Context(),
 		`UPDATE user SET
 			updated 		 = CURRENT_TIMESTAMP,
-			token_reset 	 = NULL,
-			token_reset_eat	 = NULL,
 			password_hash 	 = ?,
 			password_history = ?
 		WHERE id = ?`,
@@ -89,10 +157,34 @@ func PATCH_Auth_ResetPassword(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Logout User
+	// 	Whoever knew the previous password may still be logged in somewhere
+	SessionIDs, err := tools.DeleteUserSessions(r.Context(), tx, UserID, 0)
+	if err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	}
+	TokenCount, err := tools.DeleteUserTokens(r.Context(), tx, UserID)
+	if err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	}
+	tools.RevokeAccessTokens(SessionIDs...)
+	tools.RecordUserEvent(r, UserID, tools.EVENT_PASSWORD_RESET, "user")
+
 	// Alert User
 	go tools.EmailNotifyUserPasswordModified(
 		UserEmailAddress,
-		tools.LocalsNotifyUserPasswordModified{},
+		tools.LocalsNotifyUserPasswordModified{
+			Reset:    true,
+			Sessions: len(SessionIDs),
+			Tokens:   TokenCount,
+		},
 	)
 
 	w.WriteHeader(http.StatusNoContent)
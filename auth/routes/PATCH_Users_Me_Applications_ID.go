@@ -0,0 +1,98 @@
+package routes
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+
+	"dsoob/backend/tools"
+)
+
+func PATCH_Users_Me_Applications_ID(w http.ResponseWriter, r *http.Request) {
+
+	var Body struct {
+		Name   *string   `json:"name" validate:"omitempty,min=1,displayname"`
+		Scopes *[]string `json:"scopes" validate:"omitempty,max=16,dive,scope"`
+	}
+	if !tools.BindJSON(w, r, &Body) {
+		return
+	}
+	if Body.Name == nil && Body.Scopes == nil {
+		tools.SendClientError(w, r, tools.ERROR_BODY_EMPTY)
+		return
+	}
+	session := tools.GetSession(r)
+	ok, applicationID := tools.GetSnowflake(w, r)
+	if !ok {
+		return
+	}
+
+	// Fetch Application
+	var (
+		ApplicationName      string
+		ApplicationScopesRAW string
+		ApplicationScopes    = []string{}
+	)
+	err := tools.Database.QueryRowContext(r.Context(),
+		"SELECT name, scopes FROM application WHERE id = ? AND owner_id = ?",
+		applicationID,
+		session.UserID,
+	).Scan(
+		&ApplicationName,
+		&ApplicationScopesRAW,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		tools.SendClientError(w, r, tools.ERROR_UNKNOWN_APPLICATION)
+		return
+	}
+	if err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	}
+	if ApplicationScopesRAW != "" {
+		ApplicationScopes = strings.Split(ApplicationScopesRAW, tools.ARRAY_DELIMITER)
+	}
+
+	// Apply Edits
+	if Body.Name != nil {
+		ApplicationName = *Body.Name
+	}
+	if Body.Scopes != nil {
+		ApplicationScopes = *Body.Scopes
+		slices.Sort(ApplicationScopes)
+		ApplicationScopes = slices.Compact(ApplicationScopes)
+	}
+
+	// Update Application
+	tag, err := tools.Database.ExecContext(r.Context(),
+		`UPDATE application SET
+			updated = CURRENT_TIMESTAMP,
+			name 	= ?,
+			scopes 	= ?
+		WHERE id = ? AND owner_id = ?`,
+		ApplicationName,
+		strings.Join(ApplicationScopes, tools.ARRAY_DELIMITER),
+		applicationID,
+		session.UserID,
+	)
+	if err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	}
+	if c, err := tag.RowsAffected(); err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	} else if c == 0 {
+		tools.SendClientError(w, r, tools.ERROR_UNKNOWN_APPLICATION)
+		return
+	}
+
+	// Return Results
+	tools.SendJSON(w, r, http.StatusOK, map[string]any{
+		"id":     applicationID,
+		"name":   ApplicationName,
+		"scopes": ApplicationScopes,
+	})
+}
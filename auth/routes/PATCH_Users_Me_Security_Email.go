@@ -3,7 +3,9 @@ package routes
 import (
 	"database/sql"
 	"errors"
+	"fmt"
 	"net/http"
+	"strings"
 	"time"
 
 	"dsoob/backend/tools"
@@ -12,8 +14,7 @@ import (
 func PATCH_Users_Me_Security_Email(w http.ResponseWriter, r *http.Request) {
 
 	session := tools.GetSession(r)
-	if !session.Elevated {
-		tools.SendClientError(w, r, tools.ERROR_MFA_ESCALATION_REQUIRED)
+	if !tools.UseElevation(w, r, tools.ELEVATION_EMAIL_CHANGE) {
 		return
 	}
 
@@ -24,11 +25,20 @@ func PATCH_Users_Me_Security_Email(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	UserEmailNormalized, ok := tools.ValidateEmailAddress(w, r, Body.Email)
+	if !ok {
+		return
+	}
+
 	// Duplicate Check
+	// 	Users may switch between variations of their own address
 	var UsageEmail int
 	err := tools.Database.QueryRowContext(r.Context(),
-		"SELECT COUNT(*) FROM user WHERE email_address = LOWER(?)",
+		`SELECT COUNT(*) FROM user
+		WHERE (email_address = LOWER(?) OR email_normalized = ?) AND id != ?`,
 		Body.Email,
+		UserEmailNormalized,
+		session.UserID,
 	).Scan(
 		&UsageEmail,
 	)
@@ -41,11 +51,14 @@ func PATCH_Users_Me_Security_Email(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Update User
+	// Request Change
+	// 	The new address is held as pending until confirmed, the current address can undo the change
 	var (
-		UserEmailAddressPrevious  string
-		UserEmailVerifyToken      = tools.GenerateTokenString()
-		UserEmailVerifyExpiration = time.Now().Add(tools.TOKEN_LIFETIME_EMAIL_VERIFY)
+		UserEmailAddress     string
+		UserEmailRevert      string
+		UserEmailPending     = strings.ToLower(Body.Email)
+		UserEmailChangeToken = tools.GenerateTokenString()
+		UserEmailRevertToken = tools.GenerateTokenString()
 	)
 
 	tx, err := tools.Database.BeginTx(r.Context(), nil)
@@ -55,11 +68,22 @@ func PATCH_Users_Me_Security_Email(w http.ResponseWriter, r *http.Request) {
 	}
 	defer tx.Rollback()
 
-	err = tx.QueryRow(
+	// Consume Elevation
+	// 	High-Risk actions require a fresh elevation every time
+	if err := tools.ElevationConsume(r.Context(), tx, session.SessionID); err != nil {
+		if errors.Is(err, tools.ErrElevationConsumed) {
+			tools.SendClientError(w, r, tools.ERROR_MFA_ESCALATION_REQUIRED)
+			return
+		}
+		tools.SendServerError(w, r, err)
+		return
+	}
+
+	err = tx.QueryRowContext(r.Context(),
 		"SELECT email_address FROM user WHERE id = ?",
 		session.UserID,
 	).Scan(
-		&UserEmailAddressPrevious,
+		&UserEmailAddress,
 	)
 	if errors.Is(err, sql.ErrNoRows) {
 		tools.SendClientError(w, r, tools.ERROR_UNKNOWN_USER)
@@ -69,22 +93,46 @@ func PATCH_Users_Me_Security_Email(w http.ResponseWriter, r *http.Request) {
 		tools.SendServerError(w, r, err)
 		return
 	}
+	if UserEmailAddress == UserEmailPending {
+		tools.SendClientError(w, r, tools.ERROR_BODY_EMPTY)
+		return
+	}
 
-	_, err = tx.ExecContext(r.Context(),
-		`UPDATE user SET
-			updated			 	= CURRENT_TIMESTAMP,
-			email_verified 		= FALSE,
-			email_address 	 	= LOWER(?),
-			token_verify 	 	= ?,
-			token_verify_eat 	= ?
-		WHERE id = ?`,
-		Body.Email,
-		UserEmailVerifyToken,
-		UserEmailVerifyExpiration,
-		session.UserID,
+	// Revert Address
+	// 	Changing the address again must not invalidate an earlier revert link,
+	// 	otherwise whoever made the first change could lock out the original owner
+	err = tx.QueryRowContext(r.Context(),
+		"SELECT data FROM user_challenge WHERE user_id = ? AND purpose = ? AND expires > ?",
 		session.UserID,
+		tools.CHALLENGE_EMAIL_REVERT,
+		time.Now(),
+	).Scan(
+		&UserEmailRevert,
 	)
-	if err != nil {
+	if errors.Is(err, sql.ErrNoRows) {
+		UserEmailRevert = UserEmailAddress
+	} else if err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	}
+
+	if err := tools.ChallengeCreate(r.Context(), tx,
+		session.UserID,
+		tools.CHALLENGE_EMAIL_CHANGE,
+		UserEmailChangeToken,
+		UserEmailPending,
+		tools.TOKEN_LIFETIME_EMAIL_CHANGE,
+	); err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	}
+	if err := tools.ChallengeCreate(r.Context(), tx,
+		session.UserID,
+		tools.CHALLENGE_EMAIL_REVERT,
+		UserEmailRevertToken,
+		UserEmailRevert,
+		tools.TOKEN_LIFETIME_EMAIL_REVERT,
+	); err != nil {
 		tools.SendServerError(w, r, err)
 		return
 	}
@@ -95,15 +143,20 @@ func PATCH_Users_Me_Security_Email(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Notify User
-	go tools.EmailVerify(
-		Body.Email,
-		tools.LocalsEmailVerify{
-			Token: UserEmailVerifyToken,
+	go tools.EmailVerifyChange(
+		UserEmailPending,
+		tools.LocalsEmailVerifyChange{
+			Token:    UserEmailChangeToken,
+			Lifetime: fmt.Sprint(tools.TOKEN_LIFETIME_EMAIL_CHANGE.Hours()),
 		},
 	)
 	go tools.EmailNotifyUserEmailModified(
-		UserEmailAddressPrevious,
-		tools.LocalsNotifyUserEmailModified{},
+		UserEmailRevert,
+		tools.LocalsNotifyUserEmailModified{
+			Email:    UserEmailPending,
+			Token:    UserEmailRevertToken,
+			Lifetime: fmt.Sprint(tools.TOKEN_LIFETIME_EMAIL_REVERT.Hours() / 24),
+		},
 	)
 
 	w.WriteHeader(http.StatusNoContent)
@@ -4,6 +4,7 @@ import (
 	"database/sql"
 	"errors"
 	"net/http"
+	"slices"
 	"strings"
 
 	"dsoob/backend/tools"
@@ -18,19 +19,25 @@ func PATCH_Users_Me_Security_Password(w http.ResponseWriter, r *http.Request) {
 	if !tools.BindJSON(w, r, &Body) {
 		return
 	}
+	if tools.PasswordBreached(Body.NewPassword) {
+		tools.SendClientError(w, r, tools.ERROR_LOGIN_PASSWORD_BREACHED)
+		return
+	}
 	session := tools.GetSession(r)
 
 	// Fetch Account Password Fields
 	var (
 		UserEmailAddress       string
+		UserName               string
 		UserPasswordHash       *string
 		UserPasswordHistoryRAW string
 	)
 	err := tools.Database.QueryRowContext(r.Context(),
-		"SELECT email_address, password_hash, password_history FROM user WHERE id = ?",
+		"SELECT email_address, username, password_hash, password_history FROM user WHERE id = ?",
 		session.UserID,
 	).Scan(
 		&UserEmailAddress,
+		&UserName,
 		&UserPasswordHash,
 		&UserPasswordHistoryRAW,
 	)
@@ -55,9 +62,15 @@ func PATCH_Users_Me_Security_Password(w http.ResponseWriter, r *http.Request) {
 		tools.SendClientError(w, r, tools.ERROR_MFA_PASSWORD_INCORRECT)
 		return
 	}
+	if !tools.ValidatePasswordStrength(w, r, Body.NewPassword, UserEmailAddress, UserName) {
+		return
+	}
 
 	// Update Password History
-	UserPasswordHistory := strings.Split(UserPasswordHistoryRAW, tools.ARRAY_DELIMITER)
+	UserPasswordHistory := slices.DeleteFunc(
+		strings.Split(UserPasswordHistoryRAW, tools.ARRAY_DELIMITER),
+		func(h string) bool { return h == "" },
+	)
 	for _, oldPassword := range UserPasswordHistory {
 		if ok, err := tools.ComparePasswordHash(oldPassword, Body.NewPassword); err != nil {
 			tools.SendServerError(w, r, err)
@@ -77,8 +90,15 @@ func PATCH_Users_Me_Security_Password(w http.ResponseWriter, r *http.Request) {
 		UserPasswordHistory = UserPasswordHistory[1:]
 	}
 
+	tx, err := tools.Database.BeginTx(r.Context(), nil)
+	if err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	}
+	defer tx.Rollback()
+
 	// Update User
-	tag, err := tools.Database.ExecContext(r.Context(),
+	tag, err := tx.ExecContext(r.Context(),
 		`UPDATE user SET
 			updated			 = CURRENT_TIMESTAMP,
 			password_hash	 = ?,
@@ -100,10 +120,33 @@ func PATCH_Users_Me_Security_Password(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Logout Other Devices
+	// 	The current session is kept so the user isn't logged out of the device they used
+	SessionIDs, err := tools.DeleteUserSessions(r.Context(), tx, session.UserID, session.SessionID)
+	if err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	}
+	TokenCount, err := tools.DeleteUserTokens(r.Context(), tx, session.UserID)
+	if err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	}
+	tools.RevokeAccessTokens(SessionIDs...)
+	tools.RecordUserEvent(r, session.UserID, tools.EVENT_PASSWORD_CHANGED, "")
+
 	// Notify User
 	go tools.EmailNotifyUserPasswordModified(
 		UserEmailAddress,
-		tools.LocalsNotifyUserPasswordModified{},
+		tools.LocalsNotifyUserPasswordModified{
+			Sessions: len(SessionIDs),
+			Tokens:   TokenCount,
+		},
 	)
 
 	w.WriteHeader(http.StatusNoContent)
@@ -0,0 +1,129 @@
+package routes
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"dsoob/backend/tools"
+)
+
+func PATCH_Users_Me_Username(w http.ResponseWriter, r *http.Request) {
+
+	session := tools.GetSession(r)
+	if !tools.UseElevation(w, r, tools.ELEVATION_USERNAME) {
+		return
+	}
+
+	var Body struct {
+		Username string `json:"username" validate:"required,username"`
+	}
+	if !tools.BindJSON(w, r, &Body) {
+		return
+	}
+	Body.Username = strings.ToLower(Body.Username)
+
+	tx, err := tools.Database.BeginTx(r.Context(), nil)
+	if err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	}
+	defer tx.Rollback()
+
+	// Fetch User
+	var UserName string
+	err = tx.QueryRowContext(r.Context(),
+		"SELECT username FROM user WHERE id = ?",
+		session.UserID,
+	).Scan(
+		&UserName,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		tools.SendClientError(w, r, tools.ERROR_UNKNOWN_USER)
+		return
+	} else if err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	}
+	if UserName == Body.Username {
+		tools.SendClientError(w, r, tools.ERROR_BODY_EMPTY)
+		return
+	}
+
+	// Check Cooldown
+	var ChangedAt time.Time
+	err = tx.QueryRowContext(r.Context(),
+		"SELECT created FROM user_username WHERE user_id = ? ORDER BY id DESC LIMIT 1",
+		session.UserID,
+	).Scan(
+		&ChangedAt,
+	)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		tools.SendServerError(w, r, err)
+		return
+	}
+	if err == nil && time.Since(ChangedAt) < tools.USERNAME_CHANGE_COOLDOWN {
+		tools.SendClientError(w, r, tools.ERROR_USERNAME_COOLDOWN)
+		return
+	}
+
+	// Check for Duplicate Username
+	// 	Previous usernames of other accounts remain reserved for a while to prevent impersonation
+	var UsageUsername int
+	if err := tx.QueryRowContext(r.Context(),
+		`SELECT
+			(SELECT COUNT(*) FROM user WHERE username = ? COLLATE NOCASE) +
+			(SELECT COUNT(*) FROM user_username WHERE username = ? AND user_id != ? AND reserved_until > ?)`,
+		Body.Username,
+		Body.Username,
+		session.UserID,
+		time.Now(),
+	).Scan(
+		&UsageUsername,
+	); err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	}
+	if UsageUsername > 0 {
+		tools.SendClientError(w, r, tools.ERROR_SIGNUP_DUPLICATE_USERNAME)
+		return
+	}
+
+	// Reserve Previous Username
+	if _, err := tx.ExecContext(r.Context(),
+		"INSERT INTO user_username (id, reserved_until, user_id, username) VALUES (?, ?, ?, ?)",
+		tools.GenerateSnowflake(),
+		time.Now().Add(tools.USERNAME_RESERVATION),
+		session.UserID,
+		UserName,
+	); err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	}
+
+	// Update User
+	// 	Concurrent renames to the same username are only caught by the unique index
+	if _, err := tx.ExecContext(r.Context(),
+		"UPDATE user SET updated = CURRENT_TIMESTAMP, username = ? WHERE id = ?",
+		Body.Username,
+		session.UserID,
+	); tools.DatabaseUniqueViolation(err) {
+		tools.SendClientError(w, r, tools.ERROR_SIGNUP_DUPLICATE_USERNAME)
+		return
+	} else if err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	}
+	tools.RecordUserEvent(r, session.UserID, tools.EVENT_USERNAME_CHANGED, UserName)
+
+	// Return Results
+	tools.SendJSON(w, r, http.StatusOK, map[string]any{
+		"username": Body.Username,
+	})
+}
@@ -0,0 +1,59 @@
+package routes
+
+import (
+	"net/http"
+	"time"
+
+	"dsoob/backend/tools"
+)
+
+func POST_Admin_Invites(w http.ResponseWriter, r *http.Request) {
+
+	session := tools.GetSession(r)
+
+	var Body struct {
+		UsesLimit     int `json:"uses_limit" validate:"omitempty,min=1,max=1000"`
+		ExpiresInDays int `json:"expires_in_days" validate:"omitempty,min=1,max=365"`
+	}
+	if !tools.BindJSON(w, r, &Body) {
+		return
+	}
+
+	// Create Invite
+	// 	Unlike user invites these do not count towards any limit and may never expire
+	var (
+		InviteID        = tools.GenerateSnowflake()
+		InviteCode      = tools.GenerateInviteCode()
+		InviteUsesLimit = max(Body.UsesLimit, 1)
+		InviteExpires   *time.Time
+	)
+	if Body.ExpiresInDays > 0 {
+		t := time.Now().Add(time.Duration(Body.ExpiresInDays) * 24 * time.Hour)
+		InviteExpires = &t
+	}
+	if _, err := tools.Database.ExecContext(r.Context(),
+		"INSERT INTO user_invite (id, expires, user_id, code, uses_limit) VALUES (?, ?, ?, ?, ?)",
+		InviteID,
+		InviteExpires,
+		session.UserID,
+		InviteCode,
+		InviteUsesLimit,
+	); err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	}
+	tools.LoggerAdmin.Data(tools.INFO, "Invite Created", map[string]any{
+		"admin_id":   session.UserID,
+		"invite_id":  InviteID,
+		"uses_limit": InviteUsesLimit,
+	})
+
+	// Return Results
+	tools.SendJSON(w, r, http.StatusOK, map[string]any{
+		"id":         InviteID,
+		"expires":    InviteExpires,
+		"code":       InviteCode,
+		"uses":       0,
+		"uses_limit": InviteUsesLimit,
+	})
+}
@@ -0,0 +1,89 @@
+package routes
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"dsoob/backend/tools"
+)
+
+func POST_Admin_Users_ID_ResetPassword(w http.ResponseWriter, r *http.Request) {
+
+	session := tools.GetSession(r)
+	ok, userID := tools.GetSnowflake(w, r)
+	if !ok {
+		return
+	}
+
+	tx, err := tools.Database.BeginTx(r.Context(), nil)
+	if err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	}
+	defer tx.Rollback()
+
+	// Update User
+	// 	Clearing the password hash locks the account until the user
+	// 	completes the reset process using the link sent to them
+	var (
+		ResetToken       = tools.GenerateTokenString()
+		UserEmailAddress string
+	)
+	err = tx.QueryRowContext(r.Context(),
+		`UPDATE user SET
+			updated 		= CURRENT_TIMESTAMP,
+			password_hash	= NULL
+		WHERE id = ?
+		RETURNING email_address`,
+		userID,
+	).Scan(
+		&UserEmailAddress,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		tools.SendClientError(w, r, tools.ERROR_UNKNOWN_USER)
+		return
+	}
+	if err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	}
+	if err := tools.ChallengeCreate(r.Context(), tx,
+		userID,
+		tools.CHALLENGE_PASSWORD_RESET,
+		ResetToken,
+		"",
+		tools.TOKEN_LIFETIME_EMAIL_RESET,
+	); err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	}
+
+	// Logout User
+	SessionIDs, err := tools.DeleteUserSessions(r.Context(), tx, userID, 0)
+	if err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	}
+	tools.RevokeAccessTokens(SessionIDs...)
+	tools.RecordUserEvent(r, userID, tools.EVENT_PASSWORD_RESET, "admin")
+	tools.LoggerAdmin.Data(tools.INFO, "User Password Reset", map[string]any{
+		"admin_id": session.UserID,
+		"user_id":  userID,
+	})
+
+	// Notify User
+	go tools.EmailLoginForgotPassword(
+		UserEmailAddress,
+		tools.LocalsLoginForgotPassword{
+			Token: ResetToken,
+		},
+	)
+
+	w.WriteHeader(http.StatusNoContent)
+}
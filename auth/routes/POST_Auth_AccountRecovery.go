@@ -0,0 +1,111 @@
+package routes
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"dsoob/backend/tools"
+)
+
+// Account Recovery is the last resort for users who lost both their authenticator and recovery codes.
+// Using a valid password reset token it schedules the removal of MFA after MFA_REMOVAL_DAYS, during which
+// the owner is notified and may cancel it using the emailed link or by passing any check with their second factor.
+// Once removed a new password reset can be requested and completed using only the email address.
+func POST_Auth_AccountRecovery(w http.ResponseWriter, r *http.Request) {
+
+	var Body struct {
+		Token string `json:"token" validate:"required,token"`
+	}
+	if !tools.BindJSON(w, r, &Body) {
+		return
+	}
+
+	// Fetch Challenge
+	// 	The password reset token is left in place but will have expired long before MFA is removed,
+	// 	the user is told to request a new reset once the removal has completed
+	challenge, err := tools.ChallengeLookup(r.Context(), tools.CHALLENGE_PASSWORD_RESET, Body.Token)
+	if err == tools.ErrChallengeNotFound {
+		tools.SendClientError(w, r, tools.ERROR_UNKNOWN_USER)
+		return
+	}
+	if err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	}
+
+	// Fetch User
+	var (
+		UserID           = challenge.UserID
+		UserEmailAddress string
+		UserMFAEnabled   bool
+		UserMFARemovalAt *time.Time
+	)
+	err = tools.Database.QueryRowContext(r.Context(),
+		"SELECT email_address, mfa_enabled, mfa_removal_at FROM user WHERE id = ?",
+		UserID,
+	).Scan(
+		&UserEmailAddress,
+		&UserMFAEnabled,
+		&UserMFARemovalAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		tools.SendClientError(w, r, tools.ERROR_UNKNOWN_USER)
+		return
+	}
+	if err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	}
+	if !UserMFAEnabled {
+		tools.SendClientError(w, r, tools.ERROR_MFA_DISABLED)
+		return
+	}
+	if UserMFARemovalAt != nil {
+		tools.SendClientError(w, r, tools.ERROR_MFA_REMOVAL_PENDING)
+		return
+	}
+
+	// Schedule MFA Removal
+	var (
+		UserMFARemovalToken = tools.GenerateTokenString()
+		UserMFARemovalDate  = time.Now().AddDate(0, 0, tools.MFA_REMOVAL_DAYS)
+	)
+	tag, err := tools.Database.ExecContext(r.Context(),
+		`UPDATE user SET
+			updated 		  = CURRENT_TIMESTAMP,
+			mfa_removal_at 	  = CURRENT_TIMESTAMP,
+			token_mfa_removal = ?
+		WHERE id = ? AND mfa_removal_at IS NULL`,
+		UserMFARemovalToken,
+		UserID,
+	)
+	if err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	}
+	if c, err := tag.RowsAffected(); err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	} else if c == 0 {
+		tools.SendClientError(w, r, tools.ERROR_MFA_REMOVAL_PENDING)
+		return
+	}
+	tools.RecordUserEvent(r, UserID, tools.EVENT_MFA_REMOVAL_REQUESTED, "")
+
+	// Notify User
+	go tools.EmailNotifyUserMFARemoval(
+		UserEmailAddress,
+		tools.LocalsNotifyUserMFARemoval{
+			Token:    UserMFARemovalToken,
+			Lifetime: fmt.Sprint(tools.MFA_REMOVAL_DAYS),
+		},
+	)
+
+	// Return Results
+	tools.SendJSON(w, r, http.StatusAccepted, map[string]any{
+		"removal_at": UserMFARemovalDate.Unix(),
+	})
+}
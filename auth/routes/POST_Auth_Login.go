@@ -4,7 +4,6 @@ import (
 	"database/sql"
 	"errors"
 	"net/http"
-	"strings"
 	"time"
 
 	"dsoob/backend/tools"
@@ -33,20 +32,23 @@ func POST_Auth_Login(w http.ResponseWriter, r *http.Request) {
 		UserMFACodesRAW   string
 		UserMFACodesUsed  int
 		UserPasswordHash  *string
+		UserSuspendedAt   *time.Time
+		UserDeletedAt     *time.Time
 	)
 	err := tools.Database.QueryRowContext(r.Context(),
 		`SELECT
 			id, email_address, email_verified, ip_address,
 			mfa_enabled, mfa_secret, mfa_codes, mfa_codes_used,
-			password_hash
+			password_hash, suspended_at, deleted_at
 		FROM user WHERE email_address = LOWER(?)`,
 		Body.Email,
 	).Scan(
 		&UserID, &UserEmailAddress, &UserEmailVerified, &UserIPAddress,
 		&UserMFAEnabled, &UserMFASecret, &UserMFACodesRAW, &UserMFACodesUsed,
-		&UserPasswordHash,
+		&UserPasswordHash, &UserSuspendedAt, &UserDeletedAt,
 	)
 	if errors.Is(err, sql.ErrNoRows) {
+		tools.ProofFail(tools.GetRemoteIP(r))
 		tools.SendClientError(w, r, tools.ERROR_LOGIN_INCORRECT)
 		return
 	}
@@ -64,9 +66,19 @@ func POST_Auth_Login(w http.ResponseWriter, r *http.Request) {
 		tools.SendServerError(w, r, err)
 		return
 	} else if !ok {
+		tools.RecordUserEvent(r, UserID, tools.EVENT_LOGIN_FAILED, "password")
+		tools.ProofFail(tools.GetRemoteIP(r))
 		tools.SendClientError(w, r, tools.ERROR_LOGIN_INCORRECT)
 		return
 	}
+	if UserDeletedAt != nil {
+		tools.SendClientError(w, r, tools.ERROR_LOGIN_ACCOUNT_DELETED)
+		return
+	}
+	if UserSuspendedAt != nil {
+		tools.SendClientError(w, r, tools.ERROR_LOGIN_ACCOUNT_SUSPENDED)
+		return
+	}
 
 	// Filter: Multi-Factor Authentication
 	var (
@@ -82,54 +94,11 @@ func POST_Auth_Login(w http.ResponseWriter, r *http.Request) {
 		// User must attempt to prove ownership by entering a code generated by
 		// their authenticator app or by entering a recovery code
 
-		if Body.Passcode == "" {
-			tools.SendClientError(w, r, tools.ERROR_MFA_PASSCODE_REQUIRED)
-			return
-		}
-
-		switch len(Body.Passcode) {
-
-		// Using Passcode
-		case tools.MFA_PASSCODE_LENGTH:
-			if !tools.ValidateTOTPCode(Body.Passcode, *UserMFASecret) {
-				tools.SendClientError(w, r, tools.ERROR_MFA_PASSCODE_INCORRECT)
-				return
+		if ok, method := tools.ValidateMFA(w, r, UserID, *UserMFASecret, UserMFACodesRAW, UserMFACodesUsed, Body.Passcode); !ok {
+			if method != "" {
+				tools.RecordUserEvent(r, UserID, tools.EVENT_LOGIN_FAILED, method)
+				tools.ProofFail(tools.GetRemoteIP(r))
 			}
-
-		// Using Recovery Code
-		case tools.MFA_RECOVERY_LENGTH:
-			found := false
-			for i, recoveryCode := range strings.Split(UserMFACodesRAW, tools.ARRAY_DELIMITER) {
-				if Body.Passcode == recoveryCode {
-
-					// Code Used?
-					if (UserMFACodesUsed & (1 << i)) != 0 {
-						tools.SendClientError(w, r, tools.ERROR_MFA_RECOVERY_CODE_USED)
-						return
-					}
-
-					// Mark Recovery Code as Used
-					if _, err := tools.Database.ExecContext(r.Context(),
-						"UPDATE user SET mfa_codes_used = mfa_codes_used | ? WHERE id = ?",
-						(1 << i),
-						UserID,
-					); err != nil {
-						tools.SendServerError(w, r, err)
-						return
-					}
-
-					found = true
-					break
-				}
-			}
-			if !found {
-				tools.SendClientError(w, r, tools.ERROR_MFA_RECOVERY_CODE_INCORRECT)
-				return
-			}
-
-		default:
-			// Should have been caught by the validator!
-			tools.SendClientError(w, r, tools.ERROR_MFA_PASSCODE_INCORRECT)
 			return
 		}
 
@@ -141,29 +110,16 @@ func POST_Auth_Login(w http.ResponseWriter, r *http.Request) {
 
 		// Generate Token
 		var UserLoginVerifyToken = tools.GenerateTokenString()
-		tag, err := tools.Database.ExecContext(r.Context(),
-			`UPDATE user SET
-				updated 		 = CURRENT_TIMESTAMP,
-				token_login 	 = ?,
-				token_login_data = ?,
-				token_login_eat  = ?
-			WHERE id = ?`,
+		if err := tools.ChallengeCreate(r.Context(), tools.Database,
+			UserID,
+			tools.CHALLENGE_LOGIN_LOCATION,
 			UserLoginVerifyToken,
 			SessionAddress,
-			time.Now().Add(tools.TOKEN_LIFETIME_EMAIL_LOGIN),
-			UserID,
-		)
-		if err != nil {
+			tools.TOKEN_LIFETIME_EMAIL_LOGIN,
+		); err != nil {
 			tools.SendServerError(w, r, err)
 			return
 		}
-		if c, err := tag.RowsAffected(); err != nil {
-			tools.SendServerError(w, r, err)
-			return
-		} else if c == 0 {
-			tools.SendClientError(w, r, tools.ERROR_UNKNOWN_USER)
-			return
-		}
 
 		// Alert User
 		go tools.EmailLoginNewLocation(
@@ -181,6 +137,31 @@ func POST_Auth_Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Upgrade Password Hash
+	// 	The password is only ever known during login, so hashes using an older
+	// 	algorithm or outdated parameters are replaced whenever the user signs in
+	if tools.ComparePasswordOutdated(*UserPasswordHash) {
+		newPasswordHash, err := tools.GeneratePasswordHash(Body.Password)
+		if err != nil {
+			tools.SendServerError(w, r, err)
+			return
+		}
+		if _, err := tools.Database.ExecContext(r.Context(),
+			`UPDATE user SET
+				password_hash    = ?,
+				password_history = REPLACE(password_history, ?, ?)
+			WHERE id = ? AND password_hash = ?`,
+			newPasswordHash,
+			*UserPasswordHash,
+			newPasswordHash,
+			UserID,
+			*UserPasswordHash,
+		); err != nil {
+			tools.SendServerError(w, r, err)
+			return
+		}
+	}
+
 	// Update User
 	tag, err := tools.Database.ExecContext(r.Context(),
 		`UPDATE user SET
@@ -220,6 +201,8 @@ func POST_Auth_Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	tools.RecordUserEvent(r, UserID, tools.EVENT_LOGIN, "password")
+
 	// Alert User
 	go tools.EmailLoginNewDevice(
 		UserEmailAddress,
@@ -232,10 +215,7 @@ func POST_Auth_Login(w http.ResponseWriter, r *http.Request) {
 	)
 
 	// Send Results
-	tools.SendJSON(w, r, http.StatusOK, map[string]any{
-		"user_id":    UserID,
-		"session_id": SessionID,
-		"prefix":     tools.TOKEN_PREFIX_USER,
-		"token":      SessionToken,
-	})
+	tools.SendJSON(w, r, http.StatusOK,
+		tools.SessionTokenResponse(UserID, SessionID, SessionToken),
+	)
 }
@@ -0,0 +1,71 @@
+package routes
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"dsoob/backend/tools"
+)
+
+func POST_Auth_Login_Email(w http.ResponseWriter, r *http.Request) {
+
+	var Body struct {
+		Email string `json:"email" validate:"required,email"`
+	}
+	if !tools.BindJSON(w, r, &Body) {
+		return
+	}
+
+	// Fetch User
+	var (
+		LoginToken       = tools.GenerateTokenString()
+		LoginAddress     = tools.GetRemoteIP(r)
+		UserID           int64
+		UserEmailAddress string
+	)
+	err := tools.Database.QueryRowContext(r.Context(),
+		"SELECT id, email_address FROM user WHERE email_address = LOWER(?)",
+		Body.Email,
+	).Scan(
+		&UserID,
+		&UserEmailAddress,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	if err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	}
+
+	// Create Challenge
+	if err := tools.ChallengeCreate(r.Context(), tools.Database,
+		UserID,
+		tools.CHALLENGE_LOGIN_EMAIL,
+		LoginToken,
+		"",
+		tools.TOKEN_LIFETIME_EMAIL_LINK,
+	); err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	}
+
+	// Notify User
+	go tools.EmailLoginEmailLink(
+		UserEmailAddress,
+		tools.LocalsLoginEmailLink{
+			Token:          LoginToken,
+			Lifetime:       fmt.Sprint(tools.TOKEN_LIFETIME_EMAIL_LINK.Minutes()),
+			IpAddress:      LoginAddress,
+			Timestamp:      tools.LookupTimezone(time.Now(), LoginAddress),
+			DeviceBrowser:  tools.LookupBrowser(r.UserAgent()),
+			DeviceLocation: tools.LookupLocation(LoginAddress),
+		},
+	)
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,178 @@
+package routes
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	"dsoob/backend/tools"
+)
+
+func POST_Auth_Login_Email_Verify(w http.ResponseWriter, r *http.Request) {
+
+	var Body struct {
+		Token     string `json:"token" validate:"required,token"`
+		Passcode  string `json:"passcode" validate:"omitempty,passcode"`
+		PublicKey string `json:"public_key" validate:"required,publickey"`
+	}
+	if !tools.BindJSON(w, r, &Body) {
+		return
+	}
+
+	// Fetch Challenge
+	challenge, err := tools.ChallengeLookup(r.Context(), tools.CHALLENGE_LOGIN_EMAIL, Body.Token)
+	if err == tools.ErrChallengeNotFound {
+		tools.ProofFail(tools.GetRemoteIP(r))
+		tools.SendClientError(w, r, tools.ERROR_UNKNOWN_USER)
+		return
+	}
+	if err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	}
+
+	// Fetch User
+	var (
+		UserID           = challenge.UserID
+		UserEmailAddress string
+		UserMFAEnabled   bool
+		UserMFASecret    *string
+		UserMFACodesRAW  string
+		UserMFACodesUsed int
+		UserSuspendedAt  *time.Time
+		UserDeletedAt    *time.Time
+	)
+	err = tools.Database.QueryRowContext(r.Context(),
+		`SELECT
+			email_address, mfa_enabled, mfa_secret, mfa_codes, mfa_codes_used,
+			suspended_at, deleted_at
+		FROM user WHERE id = ?`,
+		UserID,
+	).Scan(
+		&UserEmailAddress, &UserMFAEnabled, &UserMFASecret, &UserMFACodesRAW, &UserMFACodesUsed,
+		&UserSuspendedAt, &UserDeletedAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		tools.SendClientError(w, r, tools.ERROR_UNKNOWN_USER)
+		return
+	}
+	if err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	}
+	if UserDeletedAt != nil {
+		tools.SendClientError(w, r, tools.ERROR_LOGIN_ACCOUNT_DELETED)
+		return
+	}
+	if UserSuspendedAt != nil {
+		tools.SendClientError(w, r, tools.ERROR_LOGIN_ACCOUNT_SUSPENDED)
+		return
+	}
+
+	// Filter: Multi-Factor Authentication
+	// 	The link only proves ownership of the email address, the challenge is kept
+	// 	until the passcode is correct so the user can retry with the same link
+	var (
+		SessionID        = tools.GenerateSnowflake()
+		SessionCreated   = time.Now()
+		SessionUserAgent = r.UserAgent()
+		SessionAddress   = tools.GetRemoteIP(r)
+		SessionToken     = tools.GenerateTokenString()
+	)
+	if UserMFAEnabled && UserMFASecret != nil {
+		if ok, method := tools.ValidateMFA(w, r, UserID, *UserMFASecret, UserMFACodesRAW, UserMFACodesUsed, Body.Passcode); !ok {
+			if method != "" {
+				tools.RecordUserEvent(r, UserID, tools.EVENT_LOGIN_FAILED, method)
+				tools.ProofFail(tools.GetRemoteIP(r))
+				if err := tools.ChallengeFail(r.Context(), challenge.ID); err != nil {
+					tools.LoggerDatabase.Data(tools.ERROR, "Cannot Count Challenge Attempt", map[string]any{
+						"challenge_id": challenge.ID,
+						"error":        err.Error(),
+					})
+				}
+			}
+			return
+		}
+	}
+
+	tx, err := tools.Database.BeginTx(r.Context(), nil)
+	if err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	}
+	defer tx.Rollback()
+
+	// Update User
+	// 	Following the link proves ownership of the email address, so the
+	// 	address is verified and the new location does not need to be approved
+	err = tools.ChallengeConsume(r.Context(), tx, challenge.ID)
+	if err == tools.ErrChallengeNotFound {
+		tools.SendClientError(w, r, tools.ERROR_UNKNOWN_USER)
+		return
+	}
+	if err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	}
+	tag, err := tx.ExecContext(r.Context(),
+		`UPDATE user SET
+			updated 	   = CURRENT_TIMESTAMP,
+			email_verified = TRUE,
+			ip_address 	   = ?
+		WHERE id = ?`,
+		SessionAddress,
+		UserID,
+	)
+	if err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	}
+	if c, err := tag.RowsAffected(); err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	} else if c == 0 {
+		tools.SendClientError(w, r, tools.ERROR_UNKNOWN_USER)
+		return
+	}
+
+	// Create Session
+	_, err = tx.ExecContext(r.Context(),
+		`INSERT INTO user_session (
+			id, created, user_id, token, device_ip_address, device_user_agent, device_public_key
+		) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		SessionID,
+		SessionCreated,
+		UserID,
+		SessionToken,
+		SessionAddress,
+		SessionUserAgent,
+		Body.PublicKey,
+	)
+	if err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	}
+	tools.RecordUserEvent(r, UserID, tools.EVENT_LOGIN, "email")
+
+	// Alert User
+	go tools.EmailLoginNewDevice(
+		UserEmailAddress,
+		tools.LocalsLoginNewDevice{
+			IpAddress:      SessionAddress,
+			Timestamp:      tools.LookupTimezone(time.Now(), SessionAddress),
+			DeviceBrowser:  tools.LookupBrowser(SessionUserAgent),
+			DeviceLocation: tools.LookupLocation(SessionAddress),
+		},
+	)
+
+	// Send Results
+	tools.SendJSON(w, r, http.StatusOK,
+		tools.SessionTokenResponse(UserID, SessionID, SessionToken),
+	)
+}
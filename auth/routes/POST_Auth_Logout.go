@@ -2,6 +2,7 @@ package routes
 
 import (
 	"net/http"
+	"strconv"
 
 	"dsoob/backend/tools"
 )
@@ -27,6 +28,8 @@ func POST_Auth_Logout(w http.ResponseWriter, r *http.Request) {
 		tools.SendClientError(w, r, tools.ERROR_UNKNOWN_SESSION)
 		return
 	}
+	tools.RevokeAccessTokens(session.SessionID)
+	tools.RecordUserEvent(r, session.UserID, tools.EVENT_LOGOUT, strconv.FormatInt(session.SessionID, 10))
 
 	w.WriteHeader(http.StatusNoContent)
 }
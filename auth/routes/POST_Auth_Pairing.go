@@ -0,0 +1,60 @@
+package routes
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"dsoob/backend/tools"
+)
+
+func POST_Auth_Pairing(w http.ResponseWriter, r *http.Request) {
+
+	var Body struct {
+		PublicKey string `json:"public_key" validate:"required,publickey"`
+	}
+	if !tools.BindJSON(w, r, &Body) {
+		return
+	}
+
+	// Cleanup Expired Requests
+	if _, err := tools.Database.ExecContext(r.Context(),
+		"DELETE FROM user_pairing WHERE expires < CURRENT_TIMESTAMP",
+	); err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	}
+
+	// Create Pairing Request
+	var (
+		PairingID         = tools.GenerateSnowflake()
+		PairingCode       = tools.GeneratePairingCode()
+		PairingToken      = tools.GenerateTokenString()
+		PairingExpiration = time.Now().Add(tools.TOKEN_LIFETIME_PAIRING)
+	)
+	if _, err := tools.Database.ExecContext(r.Context(),
+		`INSERT INTO user_pairing (
+			id, expires, code, token, device_ip_address, device_user_agent, device_public_key
+		) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		PairingID,
+		PairingExpiration,
+		PairingCode,
+		PairingToken,
+		tools.GetRemoteIP(r),
+		r.UserAgent(),
+		Body.PublicKey,
+	); err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	}
+
+	// Return Results
+	// 	The code (or uri as a QR Code) is shown to the user, while the token is
+	// 	kept by this device to poll for the session once the code is approved
+	tools.SendJSON(w, r, http.StatusOK, map[string]any{
+		"code":    PairingCode,
+		"uri":     fmt.Sprintf("https://%s/pair?code=%s", tools.SITE_NAME, PairingCode),
+		"token":   PairingToken,
+		"expires": PairingExpiration.Unix(),
+	})
+}
@@ -0,0 +1,157 @@
+package routes
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	"dsoob/backend/tools"
+)
+
+func POST_Auth_Pairing_Poll(w http.ResponseWriter, r *http.Request) {
+
+	var Body struct {
+		Token string `json:"token" validate:"required,token"`
+	}
+	if !tools.BindJSON(w, r, &Body) {
+		return
+	}
+
+	// Await Approval
+	// 	Clients may either poll this endpoint or keep a request open, we hold
+	// 	onto the request for a short while to reduce the amount of round trips
+	deadline := time.NewTimer(tools.PAIRING_POLL_TIMEOUT)
+	defer deadline.Stop()
+	interval := time.NewTicker(tools.PAIRING_POLL_INTERVAL)
+	defer interval.Stop()
+
+	for {
+		var PairingUserID *int64
+		err := tools.Database.QueryRowContext(r.Context(),
+			"SELECT user_id FROM user_pairing WHERE token = ? AND expires > CURRENT_TIMESTAMP",
+			Body.Token,
+		).Scan(
+			&PairingUserID,
+		)
+		if errors.Is(err, sql.ErrNoRows) {
+			tools.SendClientError(w, r, tools.ERROR_UNKNOWN_PAIRING)
+			return
+		}
+		if err != nil {
+			tools.SendServerError(w, r, err)
+			return
+		}
+		if PairingUserID != nil {
+			break
+		}
+
+		select {
+		case <-r.Context().Done():
+			return
+		case <-deadline.C:
+			tools.SendClientError(w, r, tools.ERROR_LOGIN_PAIRING_PENDING)
+			return
+		case <-interval.C:
+		}
+	}
+
+	// Consume Pairing Request
+	var (
+		UserID           int64
+		UserEmailAddress string
+		SessionID        = tools.GenerateSnowflake()
+		SessionToken     = tools.GenerateTokenString()
+		SessionAddress   string
+		SessionUserAgent string
+		SessionPublicKey string
+	)
+	tx, err := tools.Database.BeginTx(r.Context(), nil)
+	if err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRowContext(r.Context(),
+		`DELETE FROM user_pairing
+		WHERE token = ? AND user_id IS NOT NULL AND expires > CURRENT_TIMESTAMP
+		RETURNING user_id, device_ip_address, device_user_agent, device_public_key`,
+		Body.Token,
+	).Scan(
+		&UserID,
+		&SessionAddress,
+		&SessionUserAgent,
+		&SessionPublicKey,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		tools.SendClientError(w, r, tools.ERROR_UNKNOWN_PAIRING)
+		return
+	}
+	if err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	}
+
+	// Update User
+	// 	The approving device vouches for this location, so it is remembered
+	// 	in the same way a verified login would be
+	err = tx.QueryRowContext(r.Context(),
+		`UPDATE user SET
+			updated    = CURRENT_TIMESTAMP,
+			ip_address = ?
+		WHERE id = ? AND suspended_at IS NULL AND deleted_at IS NULL
+		RETURNING email_address`,
+		SessionAddress,
+		UserID,
+	).Scan(
+		&UserEmailAddress,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		tools.SendClientError(w, r, tools.ERROR_UNKNOWN_USER)
+		return
+	}
+	if err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	}
+
+	// Create Session
+	if _, err := tx.ExecContext(r.Context(),
+		`INSERT INTO user_session (
+			id, user_id, token, device_ip_address, device_user_agent, device_public_key
+		) VALUES (?, ?, ?, ?, ?, ?)`,
+		SessionID,
+		UserID,
+		SessionToken,
+		SessionAddress,
+		SessionUserAgent,
+		SessionPublicKey,
+	); err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	}
+
+	tools.RecordUserEvent(r, UserID, tools.EVENT_LOGIN, "pairing")
+
+	// Alert User
+	go tools.EmailLoginNewDevice(
+		UserEmailAddress,
+		tools.LocalsLoginNewDevice{
+			IpAddress:      SessionAddress,
+			Timestamp:      tools.LookupTimezone(time.Now(), SessionAddress),
+			DeviceBrowser:  tools.LookupBrowser(SessionUserAgent),
+			DeviceLocation: tools.LookupLocation(SessionAddress),
+		},
+	)
+
+	// Send Results
+	tools.SendJSON(w, r, http.StatusOK,
+		tools.SessionTokenResponse(UserID, SessionID, SessionToken),
+	)
+}
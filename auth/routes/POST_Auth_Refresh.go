@@ -0,0 +1,121 @@
+package routes
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"dsoob/backend/tools"
+)
+
+func POST_Auth_Refresh(w http.ResponseWriter, r *http.Request) {
+
+	var Body struct {
+		RefreshToken string `json:"refresh_token" validate:"required,token"`
+	}
+	if !tools.BindJSON(w, r, &Body) {
+		return
+	}
+
+	tx, err := tools.Database.BeginTx(r.Context(), nil)
+	if err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	}
+	defer tx.Rollback()
+
+	// Fetch Relevant Session
+	var (
+		SessionID     int64
+		SessionUserID int64
+		SessionToken  = tools.GenerateTokenString()
+	)
+	err = tx.QueryRowContext(r.Context(),
+		`SELECT s.id, s.user_id FROM user_session s
+		JOIN user u ON u.id = s.user_id
+		WHERE s.token = ? AND s.updated > ? AND u.suspended_at IS NULL AND u.deleted_at IS NULL`,
+		Body.RefreshToken,
+		time.Now().Add(-tools.TOKEN_LIFETIME_USER_REFRESH),
+	).Scan(
+		&SessionID,
+		&SessionUserID,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+
+		// Reuse Detection
+		// 	A retired token being used means that it was copied at some point, as we cannot
+		// 	tell which party is legitimate the entire session is revoked to be safe
+		var RetiredSessionID, RetiredUserID int64
+		err := tx.QueryRowContext(r.Context(),
+			"DELETE FROM user_session WHERE id = (SELECT session_id FROM user_session_retired WHERE token = ?) RETURNING id, user_id",
+			Body.RefreshToken,
+		).Scan(
+			&RetiredSessionID,
+			&RetiredUserID,
+		)
+		if errors.Is(err, sql.ErrNoRows) {
+			tools.SendClientError(w, r, tools.ERROR_GENERIC_UNAUTHORIZED)
+			return
+		}
+		if err != nil {
+			tools.SendServerError(w, r, err)
+			return
+		}
+		if err := tx.Commit(); err != nil {
+			tools.SendServerError(w, r, err)
+			return
+		}
+		tools.RevokeAccessTokens(RetiredSessionID)
+		tools.RecordUserEvent(r, RetiredUserID, tools.EVENT_SESSION_REUSE, strconv.FormatInt(RetiredSessionID, 10))
+		tools.LoggerHTTP.Data(tools.WARN, "Refresh Token Reuse Detected", map[string]any{
+			"session_id": RetiredSessionID,
+			"ip_address": tools.GetRemoteIP(r),
+		})
+
+		tools.SendClientError(w, r, tools.ERROR_GENERIC_UNAUTHORIZED)
+		return
+	}
+	if err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	}
+
+	// Rotate Refresh Token
+	if _, err := tx.ExecContext(r.Context(),
+		"INSERT INTO user_session_retired (token, session_id) VALUES (?, ?)",
+		Body.RefreshToken,
+		SessionID,
+	); err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	}
+	if _, err := tx.ExecContext(r.Context(),
+		"DELETE FROM user_session_retired WHERE session_id = ? AND created < ?",
+		SessionID,
+		time.Now().Add(-tools.TOKEN_LIFETIME_USER_REFRESH),
+	); err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	}
+	if _, err := tx.ExecContext(r.Context(),
+		"UPDATE user_session SET updated = ?, token = ? WHERE id = ?",
+		time.Now(),
+		SessionToken,
+		SessionID,
+	); err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	}
+
+	// Send Results
+	tools.SendJSON(w, r, http.StatusOK,
+		tools.SessionTokenResponse(SessionUserID, SessionID, SessionToken),
+	)
+}
@@ -4,7 +4,6 @@ import (
 	"database/sql"
 	"errors"
 	"net/http"
-	"time"
 
 	"dsoob/backend/tools"
 )
@@ -18,22 +17,14 @@ func POST_Auth_ResetPassword(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Update User
+	// Fetch User
 	var (
-		ResetTokenExpiration = time.Now().Add(tools.TOKEN_LIFETIME_EMAIL_RESET)
-		ResetToken           = tools.GenerateTokenString()
-		UserID               int64
-		UserEmailAddress     string
+		ResetToken       = tools.GenerateTokenString()
+		UserID           int64
+		UserEmailAddress string
 	)
 	err := tools.Database.QueryRowContext(r.Context(),
-		`UPDATE user SET
-			updated 		= CURRENT_TIMESTAMP,
-			token_reset_eat = ?,
-			token_reset 	= ?
-		WHERE email_address = LOWER(?)
-		RETURNING id, email_address`,
-		ResetTokenExpiration,
-		ResetToken,
+		"SELECT id, email_address FROM user WHERE email_address = LOWER(?)",
 		Body.Email,
 	).Scan(
 		&UserID,
@@ -48,6 +39,18 @@ func POST_Auth_ResetPassword(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Create Challenge
+	if err := tools.ChallengeCreate(r.Context(), tools.Database,
+		UserID,
+		tools.CHALLENGE_PASSWORD_RESET,
+		ResetToken,
+		"",
+		tools.TOKEN_LIFETIME_EMAIL_RESET,
+	); err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	}
+
 	// Notify User
 	go tools.EmailLoginForgotPassword(
 		UserEmailAddress,
@@ -0,0 +1,42 @@
+package routes
+
+import (
+	"net/http"
+	"time"
+
+	"dsoob/backend/tools"
+)
+
+func POST_Auth_Restore(w http.ResponseWriter, r *http.Request) {
+
+	var Body struct {
+		Token string `json:"token" validate:"required,token"`
+	}
+	if !tools.BindJSON(w, r, &Body) {
+		return
+	}
+
+	// Cancel Account Deletion
+	tag, err := tools.Database.ExecContext(r.Context(),
+		`UPDATE user SET
+			updated 	  = CURRENT_TIMESTAMP,
+			deleted_at 	  = NULL,
+			token_restore = NULL
+		WHERE token_restore = ? AND deleted_at > ?`,
+		Body.Token,
+		time.Now().AddDate(0, 0, -tools.DELETE_GRACE_DAYS),
+	)
+	if err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	}
+	if c, err := tag.RowsAffected(); err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	} else if c == 0 {
+		tools.SendClientError(w, r, tools.ERROR_UNKNOWN_USER)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,118 @@
+package routes
+
+import (
+	"net/http"
+
+	"dsoob/backend/tools"
+)
+
+func POST_Auth_RevertEmailChange(w http.ResponseWriter, r *http.Request) {
+
+	var Body struct {
+		Token string `json:"token" validate:"required,token"`
+	}
+	if !tools.BindJSON(w, r, &Body) {
+		return
+	}
+
+	// Fetch Challenge
+	challenge, err := tools.ChallengeLookup(r.Context(), tools.CHALLENGE_EMAIL_REVERT, Body.Token)
+	if err == tools.ErrChallengeNotFound {
+		tools.SendClientError(w, r, tools.ERROR_UNKNOWN_USER)
+		return
+	}
+	if err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	}
+
+	tx, err := tools.Database.BeginTx(r.Context(), nil)
+	if err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	}
+	defer tx.Rollback()
+
+	err = tools.ChallengeConsume(r.Context(), tx, challenge.ID)
+	if err == tools.ErrChallengeNotFound {
+		tools.SendClientError(w, r, tools.ERROR_UNKNOWN_USER)
+		return
+	}
+	if err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	}
+	if err := tools.ChallengeDelete(r.Context(), tx, challenge.UserID, tools.CHALLENGE_EMAIL_CHANGE); err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	}
+
+	// Duplicate Check
+	// 	The previous address is released once the change is confirmed and may have been claimed since,
+	// 	addresses from before normalization was introduced may not normalize and are restored as-is
+	var UserEmailNormalized *string
+	if normalized, err := tools.EmailNormalize(challenge.Data); err == nil {
+		UserEmailNormalized = &normalized
+	}
+	var UsageEmail int
+	err = tx.QueryRowContext(r.Context(),
+		`SELECT COUNT(*) FROM user
+		WHERE (email_address = ? OR email_normalized = ?) AND id != ?`,
+		challenge.Data,
+		UserEmailNormalized,
+		challenge.UserID,
+	).Scan(
+		&UsageEmail,
+	)
+	if err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	}
+	if UsageEmail > 0 {
+		tools.SendClientError(w, r, tools.ERROR_SIGNUP_DUPLICATE_EMAIL)
+		return
+	}
+
+	// Restore User
+	tag, err := tx.ExecContext(r.Context(),
+		`UPDATE user SET
+			updated 		 = CURRENT_TIMESTAMP,
+			email_address 	 = ?,
+			email_normalized = ?,
+			email_verified 	 = TRUE,
+			token_verify 	 = NULL,
+			token_verify_eat = NULL
+		WHERE id = ?`,
+		challenge.Data,
+		UserEmailNormalized,
+		challenge.UserID,
+	)
+	if err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	}
+	if c, err := tag.RowsAffected(); err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	} else if c == 0 {
+		tools.SendClientError(w, r, tools.ERROR_UNKNOWN_USER)
+		return
+	}
+
+	// Logout User
+	// 	Whoever requested the change may still be logged in somewhere
+	SessionIDs, err := tools.DeleteUserSessions(r.Context(), tx, challenge.UserID, 0)
+	if err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	}
+	tools.RevokeAccessTokens(SessionIDs...)
+	tools.RecordUserEvent(r, challenge.UserID, tools.EVENT_EMAIL_REVERTED, challenge.Data)
+
+	w.WriteHeader(http.StatusNoContent)
+}
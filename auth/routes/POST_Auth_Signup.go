@@ -1,6 +1,8 @@
 package routes
 
 import (
+	"database/sql"
+	"errors"
 	"net/http"
 	"time"
 
@@ -13,19 +15,51 @@ func POST_Auth_Signup(w http.ResponseWriter, r *http.Request) {
 		Email    string `json:"email" validate:"required,email"`
 		Username string `json:"username" validate:"required,username"`
 		Password string `json:"password" validate:"required,password"`
+		Invite   string `json:"invite" validate:"omitempty,invitecode"`
 	}
 	if !tools.BindJSON(w, r, &Body) {
 		return
 	}
 
+	// Filter: Registration Mode
+	// 	Unknown modes are treated as closed to fail safely on typos
+	switch tools.REGISTRATION_MODE {
+	case tools.REGISTRATION_OPEN:
+	case tools.REGISTRATION_INVITE:
+		if Body.Invite == "" {
+			tools.SendClientError(w, r, tools.ERROR_SIGNUP_INVITE_REQUIRED)
+			return
+		}
+	default:
+		tools.SendClientError(w, r, tools.ERROR_SIGNUP_CLOSED)
+		return
+	}
+	UserEmailNormalized, ok := tools.ValidateEmailAddress(w, r, Body.Email)
+	if !ok {
+		return
+	}
+	if tools.PasswordBreached(Body.Password) {
+		tools.SendClientError(w, r, tools.ERROR_LOGIN_PASSWORD_BREACHED)
+		return
+	}
+	if !tools.ValidatePasswordStrength(w, r, Body.Password, Body.Email, Body.Username) {
+		return
+	}
+
 	// Check for Duplicate Email or Username
+	// 	Previous usernames of other accounts remain reserved for a while to prevent impersonation,
+	// 	variations of an email address delivering to the same mailbox count as the same address
 	var UsageUsername, UsageEmail int
 	if err := tools.Database.QueryRowContext(r.Context(),
 		`SELECT
-			(SELECT COUNT(*) FROM user WHERE username      = LOWER(?)),
-			(SELECT COUNT(*) FROM user WHERE email_address = LOWER(?))`,
+			(SELECT COUNT(*) FROM user WHERE username = ? COLLATE NOCASE) +
+			(SELECT COUNT(*) FROM user_username WHERE username = ? AND reserved_until > ?),
+			(SELECT COUNT(*) FROM user WHERE email_address = LOWER(?) OR email_normalized = ?)`,
 		Body.Username,
+		Body.Username,
+		time.Now(),
 		Body.Email,
+		UserEmailNormalized,
 	).Scan(
 		&UsageUsername,
 		&UsageEmail,
@@ -52,24 +86,62 @@ func POST_Auth_Signup(w http.ResponseWriter, r *http.Request) {
 		tools.SendServerError(w, r, err)
 		return
 	}
-	if _, err := tools.Database.ExecContext(r.Context(),
+
+	tx, err := tools.Database.BeginTx(r.Context(), nil)
+	if err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	}
+	defer tx.Rollback()
+
+	// Consume Invite
+	// 	Invites stop working once their creator is suspended or deleted
+	var UserInvitedBy *int64
+	if Body.Invite != "" {
+		err := tx.QueryRowContext(r.Context(),
+			`UPDATE user_invite SET
+				uses = uses + 1
+			WHERE code = UPPER(?) AND uses < uses_limit AND (expires IS NULL OR expires > ?)
+			AND user_id IN (SELECT id FROM user WHERE suspended_at IS NULL AND deleted_at IS NULL)
+			RETURNING user_id`,
+			Body.Invite,
+			time.Now(),
+		).Scan(
+			&UserInvitedBy,
+		)
+		if errors.Is(err, sql.ErrNoRows) {
+			tools.SendClientError(w, r, tools.ERROR_SIGNUP_INVITE_INVALID)
+			return
+		}
+		if err != nil {
+			tools.SendServerError(w, r, err)
+			return
+		}
+	}
+
+	if _, err := tx.ExecContext(r.Context(),
 		`INSERT INTO user (
 			id,
 			email_address,
+			email_normalized,
 			ip_address,
 			password_hash,
 			password_history,
 			token_verify,
 			token_verify_eat,
+			invited_by,
 			username,
 			displayname
-		) VALUES (?, LOWER(?), ?, ?, ?, ?, ?, LOWER(?), ?)`,
+		) VALUES (?, LOWER(?), ?, ?, ?, ?, ?, ?, ?, LOWER(?), ?)`,
 		UserID,
 		Body.Email,
+		UserEmailNormalized,
 		tools.GetRemoteIP(r),
 		UserPasswordHash,
+		UserPasswordHash,
 		UserEmailVerifyToken,
 		time.Now().Add(tools.TOKEN_LIFETIME_EMAIL_VERIFY),
+		UserInvitedBy,
 		Body.Username,
 		Body.Username,
 	); err != nil {
@@ -77,6 +149,11 @@ func POST_Auth_Signup(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err := tx.Commit(); err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	}
+
 	// Notify User
 	go tools.EmailVerify(
 		Body.Email,
@@ -0,0 +1,115 @@
+package routes
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"dsoob/backend/tools"
+)
+
+func POST_Auth_VerifyEmailChange(w http.ResponseWriter, r *http.Request) {
+
+	var Body struct {
+		Token string `json:"token" validate:"required,token"`
+	}
+	if !tools.BindJSON(w, r, &Body) {
+		return
+	}
+
+	// Fetch Challenge
+	challenge, err := tools.ChallengeLookup(r.Context(), tools.CHALLENGE_EMAIL_CHANGE, Body.Token)
+	if err == tools.ErrChallengeNotFound {
+		tools.SendClientError(w, r, tools.ERROR_UNKNOWN_USER)
+		return
+	}
+	if err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	}
+
+	tx, err := tools.Database.BeginTx(r.Context(), nil)
+	if err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	}
+	defer tx.Rollback()
+
+	err = tools.ChallengeConsume(r.Context(), tx, challenge.ID)
+	if err == tools.ErrChallengeNotFound {
+		tools.SendClientError(w, r, tools.ERROR_UNKNOWN_USER)
+		return
+	}
+	if err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	}
+
+	// Duplicate Check
+	// 	The address may have been claimed by another account while the change was pending
+	UserEmailNormalized, err := tools.EmailNormalize(challenge.Data)
+	if err != nil {
+		tools.SendClientError(w, r, tools.ERROR_SIGNUP_EMAIL_INVALID)
+		return
+	}
+	var UsageEmail int
+	err = tx.QueryRowContext(r.Context(),
+		`SELECT COUNT(*) FROM user
+		WHERE (email_address = ? OR email_normalized = ?) AND id != ?`,
+		challenge.Data,
+		UserEmailNormalized,
+		challenge.UserID,
+	).Scan(
+		&UsageEmail,
+	)
+	if err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	}
+	if UsageEmail > 0 {
+		tools.SendClientError(w, r, tools.ERROR_SIGNUP_DUPLICATE_EMAIL)
+		return
+	}
+
+	// Update User
+	// 	Following the link proves ownership of the new address
+	var UserEmailAddressPrevious string
+	err = tx.QueryRowContext(r.Context(),
+		"SELECT email_address FROM user WHERE id = ?",
+		challenge.UserID,
+	).Scan(
+		&UserEmailAddressPrevious,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		tools.SendClientError(w, r, tools.ERROR_UNKNOWN_USER)
+		return
+	}
+	if err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	}
+	if _, err := tx.ExecContext(r.Context(),
+		`UPDATE user SET
+			updated 		 = CURRENT_TIMESTAMP,
+			email_address 	 = ?,
+			email_normalized = ?,
+			email_verified 	 = TRUE,
+			token_verify 	 = NULL,
+			token_verify_eat = NULL
+		WHERE id = ?`,
+		challenge.Data,
+		UserEmailNormalized,
+		challenge.UserID,
+	); err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	}
+	tools.RecordUserEvent(r, challenge.UserID, tools.EVENT_EMAIL_CHANGED, UserEmailAddressPrevious)
+
+	w.WriteHeader(http.StatusNoContent)
+}
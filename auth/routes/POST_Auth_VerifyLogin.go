@@ -15,28 +15,49 @@ func POST_Auth_VerifyLogin(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Update User
-	tag, err := tools.Database.ExecContext(r.Context(),
-		`UPDATE user SET
-			updated 		 = CURRENT_TIMESTAMP,
-			ip_address 		 = token_login_data,
-			token_login 	 = NULL,
-			token_login_data = NULL,
-			token_login_eat  = NULL
-		WHERE token_login = ? AND token_login_eat > CURRENT_TIMESTAMP`,
-		Body.Token,
-	)
+	// Fetch Challenge
+	challenge, err := tools.ChallengeLookup(r.Context(), tools.CHALLENGE_LOGIN_LOCATION, Body.Token)
+	if err == tools.ErrChallengeNotFound {
+		tools.SendClientError(w, r, tools.ERROR_UNKNOWN_USER)
+		return
+	}
 	if err != nil {
 		tools.SendServerError(w, r, err)
 		return
 	}
-	if c, err := tag.RowsAffected(); err != nil {
+
+	tx, err := tools.Database.BeginTx(r.Context(), nil)
+	if err != nil {
 		tools.SendServerError(w, r, err)
 		return
-	} else if c == 0 {
+	}
+	defer tx.Rollback()
+
+	// Update User
+	// 	The challenge holds the IP address of the location being approved
+	err = tools.ChallengeConsume(r.Context(), tx, challenge.ID)
+	if err == tools.ErrChallengeNotFound {
 		tools.SendClientError(w, r, tools.ERROR_UNKNOWN_USER)
 		return
 	}
+	if err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	}
+	if _, err := tx.ExecContext(r.Context(),
+		"UPDATE user SET updated = CURRENT_TIMESTAMP, ip_address = ? WHERE id = ?",
+		challenge.Data,
+		challenge.UserID,
+	); err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	}
+	tools.RecordUserEvent(r, challenge.UserID, tools.EVENT_LOGIN_LOCATION_APPROVED, challenge.Data)
 
 	w.WriteHeader(http.StatusNoContent)
 }
@@ -2,10 +2,7 @@ package routes
 
 import (
 	"dsoob/backend/tools"
-	"fmt"
 	"net/http"
-	"strings"
-	"time"
 )
 
 func POST_Users_Bulk(w http.ResponseWriter, r *http.Request) {
@@ -17,77 +14,12 @@ func POST_Users_Bulk(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Build Query
-	placeholders := make([]string, len(Body.UserIDs))
-	arguments := make([]any, len(Body.UserIDs))
-	for i, id := range Body.UserIDs {
-		placeholders[i] = "?"
-		arguments[i] = id
-	}
-	query := fmt.Sprintf(
-		`SELECT
-			id, created, username, displayname,
-			subtitle, biography, avatar_hash, banner_hash,
-			accent_banner, accent_border, accent_background
-		FROM user WHERE id IN (%s)`,
-		strings.Join(placeholders, ","),
-	)
-
 	// Fetch Users
-	rows, err := tools.Database.QueryContext(r.Context(), query, arguments...)
+	UserItems, _, err := tools.ProfileQueryBulk(r.Context(), Body.UserIDs)
 	if err != nil {
 		tools.SendServerError(w, r, err)
 		return
 	}
-	defer rows.Close()
-
-	var (
-		UserItems            = make([]map[string]any, 0, 100)
-		UserID               int64
-		UserCreated          time.Time
-		UserName             string
-		UserDisplayname      string
-		UserSubtitle         *string
-		UserBiography        *string
-		UserAvatarHash       *string
-		UserBannerHash       *string
-		UserAccentBanner     *int
-		UserAccentBorder     *int
-		UserAccentBackground *int
-	)
-	for rows.Next() {
-		if err := rows.Scan(
-			&UserID,
-			&UserCreated,
-			&UserName,
-			&UserDisplayname,
-			&UserSubtitle,
-			&UserBiography,
-			&UserAvatarHash,
-			&UserBannerHash,
-			&UserAccentBanner,
-			&UserAccentBorder,
-			&UserAccentBackground,
-		); err != nil {
-			tools.SendServerError(w, r, err)
-			return
-		}
-
-		UserItems = append(UserItems, map[string]any{
-			"id":                UserID,
-			"created":           UserCreated,
-			"username":          UserName,
-			"displayname":       UserDisplayname,
-			"subtitle":          UserSubtitle,
-			"biography":         UserBiography,
-			"avatar":            UserAvatarHash,
-			"banner":            UserBannerHash,
-			"accent_banner":     UserAccentBanner,
-			"accent_border":     UserAccentBorder,
-			"accent_background": UserAccentBackground,
-		})
-
-	}
 
 	// Return Results
 	tools.SendJSON(w, r, http.StatusOK, UserItems)
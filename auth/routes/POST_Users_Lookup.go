@@ -0,0 +1,31 @@
+package routes
+
+import (
+	"dsoob/backend/tools"
+	"net/http"
+)
+
+func POST_Users_Lookup(w http.ResponseWriter, r *http.Request) {
+
+	var Body struct {
+		UserIDs []int64 `json:"user_ids" validate:"min=1,max=100"`
+	}
+	if !tools.BindJSON(w, r, &Body) {
+		return
+	}
+
+	// Fetch Users
+	// 	Users are returned in the order they were requested, any which
+	// 	do not exist or are pending deletion are listed as unknown instead
+	UserItems, UserUnknown, err := tools.ProfileQueryBulk(r.Context(), Body.UserIDs)
+	if err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	}
+
+	// Return Results
+	tools.SendJSON(w, r, http.StatusOK, map[string]any{
+		"users":   UserItems,
+		"unknown": UserUnknown,
+	})
+}
@@ -0,0 +1,70 @@
+package routes
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+
+	"dsoob/backend/tools"
+)
+
+func POST_Users_Me_Applications(w http.ResponseWriter, r *http.Request) {
+
+	session := tools.GetSession(r)
+	if !tools.UseElevation(w, r, tools.ELEVATION_APPLICATIONS) {
+		return
+	}
+
+	var Body struct {
+		Name   string   `json:"name" validate:"required,displayname"`
+		Scopes []string `json:"scopes" validate:"max=16,dive,scope"`
+	}
+	if !tools.BindJSON(w, r, &Body) {
+		return
+	}
+	slices.Sort(Body.Scopes)
+	Body.Scopes = slices.Compact(Body.Scopes)
+
+	// Check Application Limit
+	var ApplicationCount int
+	if err := tools.Database.QueryRowContext(r.Context(),
+		"SELECT COUNT(*) FROM application WHERE owner_id = ?",
+		session.UserID,
+	).Scan(
+		&ApplicationCount,
+	); err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	}
+	if ApplicationCount >= tools.APPLICATION_LIMIT {
+		tools.SendClientError(w, r, tools.ERROR_APPLICATION_LIMIT)
+		return
+	}
+
+	// Create Application
+	var (
+		ApplicationID     = tools.GenerateSnowflake()
+		ApplicationSecret = tools.GenerateTokenString()
+	)
+	if _, err := tools.Database.ExecContext(r.Context(),
+		"INSERT INTO application (id, owner_id, name, secret, scopes) VALUES (?, ?, ?, ?, ?)",
+		ApplicationID,
+		session.UserID,
+		Body.Name,
+		ApplicationSecret,
+		strings.Join(Body.Scopes, tools.ARRAY_DELIMITER),
+	); err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	}
+
+	// Return Results
+	// 	The token is only ever shown once, afterwards it must be reset
+	tools.SendJSON(w, r, http.StatusOK, map[string]any{
+		"id":     ApplicationID,
+		"name":   Body.Name,
+		"scopes": Body.Scopes,
+		"prefix": tools.TOKEN_PREFIX_BOT,
+		"token":  ApplicationSecret,
+	})
+}
@@ -0,0 +1,48 @@
+package routes
+
+import (
+	"net/http"
+
+	"dsoob/backend/tools"
+)
+
+func POST_Users_Me_Applications_ID_Secret(w http.ResponseWriter, r *http.Request) {
+
+	session := tools.GetSession(r)
+	if !tools.UseElevation(w, r, tools.ELEVATION_APPLICATIONS) {
+		return
+	}
+	ok, applicationID := tools.GetSnowflake(w, r)
+	if !ok {
+		return
+	}
+
+	// Reset Application Secret
+	ApplicationSecret := tools.GenerateTokenString()
+	tag, err := tools.Database.ExecContext(r.Context(),
+		`UPDATE application SET
+			updated = CURRENT_TIMESTAMP,
+			secret 	= ?
+		WHERE id = ? AND owner_id = ?`,
+		ApplicationSecret,
+		applicationID,
+		session.UserID,
+	)
+	if err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	}
+	if c, err := tag.RowsAffected(); err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	} else if c == 0 {
+		tools.SendClientError(w, r, tools.ERROR_UNKNOWN_APPLICATION)
+		return
+	}
+
+	// Return Results
+	tools.SendJSON(w, r, http.StatusOK, map[string]any{
+		"prefix": tools.TOKEN_PREFIX_BOT,
+		"token":  ApplicationSecret,
+	})
+}
@@ -0,0 +1,52 @@
+package routes
+
+import (
+	"net/http"
+	"time"
+
+	"dsoob/backend/tools"
+)
+
+func POST_Users_Me_Export(w http.ResponseWriter, r *http.Request) {
+
+	session := tools.GetSession(r)
+	if !tools.UseElevation(w, r, tools.ELEVATION_EXPORT) {
+		return
+	}
+
+	// Create Export
+	// 	Only one export may be available at a time, building them is expensive!
+	var (
+		ExportID      = tools.GenerateSnowflake()
+		ExportToken   = tools.GenerateTokenString()
+		ExportExpires = time.Now().Add(tools.TOKEN_LIFETIME_EXPORT)
+	)
+	tag, err := tools.Database.ExecContext(r.Context(),
+		`INSERT INTO user_export (id, expires, user_id, token)
+		SELECT ?, ?, ?, ?
+		WHERE NOT EXISTS (SELECT 1 FROM user_export WHERE user_id = ? AND expires > ?)`,
+		ExportID,
+		ExportExpires,
+		session.UserID,
+		ExportToken,
+		session.UserID,
+		time.Now(),
+	)
+	if err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	}
+	if c, err := tag.RowsAffected(); err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	} else if c == 0 {
+		tools.SendClientError(w, r, tools.ERROR_EXPORT_ALREADY_REQUESTED)
+		return
+	}
+
+	// Build Export
+	// 	The user is emailed a download link once it's ready
+	go tools.ExportUserData(session.UserID, ExportID, ExportToken)
+
+	w.WriteHeader(http.StatusAccepted)
+}
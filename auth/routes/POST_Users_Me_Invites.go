@@ -0,0 +1,82 @@
+package routes
+
+import (
+	"net/http"
+	"time"
+
+	"dsoob/backend/tools"
+)
+
+func POST_Users_Me_Invites(w http.ResponseWriter, r *http.Request) {
+
+	session := tools.GetSession(r)
+
+	// Invites Restricted?
+	// 	Setting INVITE_LIMIT to zero leaves invites to administrators so the user base cannot grow on its own
+	if tools.INVITE_LIMIT <= 0 {
+		tools.SendClientError(w, r, tools.ERROR_INVITE_DISABLED)
+		return
+	}
+
+	var Body struct {
+		UsesLimit     int `json:"uses_limit" validate:"omitempty,min=1"`
+		ExpiresInDays int `json:"expires_in_days" validate:"omitempty,min=1,max=30"`
+	}
+	if !tools.BindJSON(w, r, &Body) {
+		return
+	}
+	if Body.UsesLimit > tools.INVITE_USES_LIMIT {
+		tools.SendClientError(w, r, tools.ERROR_BODY_INVALID_FIELD)
+		return
+	}
+
+	// Check Invite Limit
+	// 	Only invites which can still be used count towards the limit
+	var InviteCount int
+	if err := tools.Database.QueryRowContext(r.Context(),
+		`SELECT COUNT(*) FROM user_invite
+		WHERE user_id = ? AND uses < uses_limit AND (expires IS NULL OR expires > ?)`,
+		session.UserID,
+		time.Now(),
+	).Scan(
+		&InviteCount,
+	); err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	}
+	if InviteCount >= tools.INVITE_LIMIT {
+		tools.SendClientError(w, r, tools.ERROR_INVITE_LIMIT)
+		return
+	}
+
+	// Create Invite
+	var (
+		InviteID        = tools.GenerateSnowflake()
+		InviteCode      = tools.GenerateInviteCode()
+		InviteUsesLimit = max(Body.UsesLimit, 1)
+		InviteExpires   = time.Now().Add(tools.INVITE_LIFETIME)
+	)
+	if Body.ExpiresInDays > 0 {
+		InviteExpires = time.Now().Add(time.Duration(Body.ExpiresInDays) * 24 * time.Hour)
+	}
+	if _, err := tools.Database.ExecContext(r.Context(),
+		"INSERT INTO user_invite (id, expires, user_id, code, uses_limit) VALUES (?, ?, ?, ?, ?)",
+		InviteID,
+		InviteExpires,
+		session.UserID,
+		InviteCode,
+		InviteUsesLimit,
+	); err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	}
+
+	// Return Results
+	tools.SendJSON(w, r, http.StatusOK, map[string]any{
+		"id":         InviteID,
+		"expires":    InviteExpires,
+		"code":       InviteCode,
+		"uses":       0,
+		"uses_limit": InviteUsesLimit,
+	})
+}
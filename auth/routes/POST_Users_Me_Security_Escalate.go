@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"slices"
 	"strings"
 	"time"
 
@@ -14,8 +15,9 @@ import (
 func POST_Users_Me_Security_Escalate(w http.ResponseWriter, r *http.Request) {
 
 	var Body struct {
-		Passcode string `json:"passcode" validate:"omitempty,passcode"`
-		Password string `json:"password" validate:"omitempty,password"`
+		Passcode string   `json:"passcode" validate:"omitempty,passcode"`
+		Password string   `json:"password" validate:"omitempty,password"`
+		Actions  []string `json:"actions" validate:"max=16,dive,elevation"`
 	}
 	if !tools.BindJSON(w, r, &Body) {
 		return
@@ -24,21 +26,19 @@ func POST_Users_Me_Security_Escalate(w http.ResponseWriter, r *http.Request) {
 
 	// Fetch Account with MFA Fields
 	var (
-		UserEmailAddress            string
-		UserEmailVerified           bool
-		UserMFAEnabled              bool
-		UserMFASecret               *string
-		UserMFACodesRAW             string
-		UserMFACodesUsed            int
-		UserPasswordHash            *string
-		UserEmailPasscode           *string
-		UserEmailPasscodeExpiration *time.Time
+		UserEmailAddress  string
+		UserEmailVerified bool
+		UserMFAEnabled    bool
+		UserMFASecret     *string
+		UserMFACodesRAW   string
+		UserMFACodesUsed  int
+		UserPasswordHash  *string
 	)
 	err := tools.Database.QueryRowContext(r.Context(),
 		`SELECT
 			email_address, email_verified, mfa_enabled,
 			mfa_secret, mfa_codes, mfa_codes_used,
-			password_hash, token_passcode, token_passcode_eat
+			password_hash
 		FROM user WHERE id = ?`,
 		session.UserID,
 	).Scan(
@@ -49,8 +49,6 @@ func POST_Users_Me_Security_Escalate(w http.ResponseWriter, r *http.Request) {
 		&UserMFACodesRAW,
 		&UserMFACodesUsed,
 		&UserPasswordHash,
-		&UserEmailPasscode,
-		&UserEmailPasscodeExpiration,
 	)
 	if errors.Is(err, sql.ErrNoRows) {
 		tools.SendClientError(w, r, tools.ERROR_UNKNOWN_USER)
@@ -62,62 +60,18 @@ func POST_Users_Me_Security_Escalate(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Attempt Multi-Factor Authentication
+	var EscalationMethod string
 	if UserMFAEnabled && UserMFASecret != nil {
 
 		// Method: TOTP Verification
 		// User must prove their ownership by entering a code generated by an external application
 		// or by entering a unused recovery code
 
-		if Body.Passcode == "" {
-			tools.SendClientError(w, r, tools.ERROR_MFA_PASSCODE_REQUIRED)
-			return
-		}
-
-		switch len(Body.Passcode) {
-
-		// Using Passcode
-		case tools.MFA_PASSCODE_LENGTH:
-			if !tools.ValidateTOTPCode(Body.Passcode, *UserMFASecret) {
-				tools.SendClientError(w, r, tools.ERROR_MFA_PASSCODE_INCORRECT)
-				return
-			}
-
-		// Using Recovery Code
-		case tools.MFA_RECOVERY_LENGTH:
-			found := false
-			for i, recoveryCode := range strings.Split(UserMFACodesRAW, tools.ARRAY_DELIMITER) {
-				if Body.Passcode == recoveryCode {
-
-					// Code Used?
-					if (UserMFACodesUsed & (1 << i)) != 0 {
-						tools.SendClientError(w, r, tools.ERROR_MFA_RECOVERY_CODE_USED)
-						return
-					}
-
-					// Mark Recovery Code as Used
-					if _, err := tools.Database.ExecContext(r.Context(),
-						"UPDATE user SET mfa_codes_used = mfa_codes_used | ? WHERE id = ?",
-						(1 << i),
-						session.UserID,
-					); err != nil {
-						tools.SendServerError(w, r, err)
-						return
-					}
-
-					found = true
-					break
-				}
-			}
-			if !found {
-				tools.SendClientError(w, r, tools.ERROR_MFA_RECOVERY_CODE_INCORRECT)
-				return
-			}
-
-		default:
-			// Should have been caught by the validator!
-			tools.SendClientError(w, r, tools.ERROR_MFA_PASSCODE_INCORRECT)
+		ok, method := tools.ValidateMFA(w, r, session.UserID, *UserMFASecret, UserMFACodesRAW, UserMFACodesUsed, Body.Passcode)
+		if !ok {
 			return
 		}
+		EscalationMethod = method
 
 	} else if UserEmailVerified {
 
@@ -126,20 +80,14 @@ func POST_Users_Me_Security_Escalate(w http.ResponseWriter, r *http.Request) {
 
 		if Body.Passcode == "" {
 
-			// Update User
-			var (
-				NewPasscode           = tools.GeneratePasscode()
-				NewPasscodeExpiration = time.Now().Add(tools.TOKEN_LIFETIME_EMAIL_PASSCODE)
-			)
-			if _, err = tools.Database.ExecContext(r.Context(),
-				`UPDATE user SET
-					updated 		   = CURRENT_TIMESTAMP,
-					token_passcode 	   = ?,
-					token_passcode_eat = ?
-				WHERE id = ?`,
-				NewPasscode,
-				NewPasscodeExpiration,
+			// Create Challenge
+			NewPasscode := tools.GeneratePasscode()
+			if err := tools.ChallengeCreate(r.Context(), tools.Database,
 				session.UserID,
+				tools.CHALLENGE_ESCALATION,
+				NewPasscode,
+				"",
+				tools.TOKEN_LIFETIME_EMAIL_PASSCODE,
 			); err != nil {
 				tools.SendServerError(w, r, err)
 				return
@@ -160,10 +108,21 @@ func POST_Users_Me_Security_Escalate(w http.ResponseWriter, r *http.Request) {
 		} else {
 
 			// Match Passcode
-			if !strings.EqualFold(Body.Passcode, *UserEmailPasscode) {
+			// 	Passcodes are single use and discarded after too many incorrect attempts
+			_, err := tools.ChallengeVerify(r.Context(), session.UserID, tools.CHALLENGE_ESCALATION, Body.Passcode)
+			if err == tools.ErrChallengeNotFound {
+				tools.SendClientError(w, r, tools.ERROR_MFA_PASSCODE_EXPIRED)
+				return
+			}
+			if err == tools.ErrChallengeIncorrect {
 				tools.SendClientError(w, r, tools.ERROR_MFA_PASSCODE_INCORRECT)
 				return
 			}
+			if err != nil {
+				tools.SendServerError(w, r, err)
+				return
+			}
+			EscalationMethod = tools.ELEVATION_METHOD_EMAIL
 
 		}
 
@@ -178,6 +137,7 @@ func POST_Users_Me_Security_Escalate(w http.ResponseWriter, r *http.Request) {
 			tools.SendClientError(w, r, tools.ERROR_MFA_PASSWORD_INCORRECT)
 			return
 		}
+		EscalationMethod = tools.ELEVATION_METHOD_PASSWORD
 
 	} else {
 
@@ -188,18 +148,41 @@ func POST_Users_Me_Security_Escalate(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Mark Current Session as Elevated
-	elevatedUntil := time.Now().Add(tools.TOKEN_LIFETIME_USER_ELEVATION)
+	// 	High-Risk actions are never granted unless requested and only for a short while,
+	// 	the strongest method available to the account is always the one used above (see ElevationMethods)
+	var (
+		elevatedActions = tools.ELEVATION_DEFAULT
+		elevatedUntil   = time.Now().Add(tools.TOKEN_LIFETIME_USER_ELEVATION)
+	)
+	if len(Body.Actions) > 0 {
+		elevatedActions = slices.Compact(slices.Sorted(slices.Values(Body.Actions)))
+		for _, action := range elevatedActions {
+			if slices.Contains(tools.ELEVATION_HIGH_RISK, action) {
+				elevatedUntil = time.Now().Add(tools.TOKEN_LIFETIME_USER_HIGH_RISK)
+				break
+			}
+		}
+	}
 	if _, err := tools.Database.ExecContext(r.Context(),
-		"UPDATE user_session SET elevated_until = ? WHERE id = ?",
+		`UPDATE user_session SET
+			elevated_until   = ?,
+			elevated_method  = ?,
+			elevated_actions = ?
+		WHERE id = ?`,
 		elevatedUntil,
+		EscalationMethod,
+		strings.Join(elevatedActions, tools.ARRAY_DELIMITER),
 		session.SessionID,
 	); err != nil {
 		tools.SendServerError(w, r, err)
 		return
 	}
+	tools.RecordUserEvent(r, session.UserID, tools.EVENT_ESCALATION, EscalationMethod)
 
 	// Return Results
 	tools.SendJSON(w, r, http.StatusOK, map[string]any{
 		"elevated_until": elevatedUntil.Unix(),
+		"method":         EscalationMethod,
+		"actions":        elevatedActions,
 	})
 }
@@ -45,13 +45,15 @@ func POST_Users_Me_Security_MFA_Setup(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Update User
+	// 	Enabling MFA again cancels any removal requested while it was disabled
 	if _, err := tools.Database.ExecContext(r.Context(),
-		"UPDATE user SET mfa_enabled = TRUE WHERE id = ?",
+		"UPDATE user SET mfa_enabled = TRUE, mfa_removal_at = NULL, token_mfa_removal = NULL WHERE id = ?",
 		session.UserID,
 	); err != nil {
 		tools.SendServerError(w, r, err)
 		return
 	}
+	tools.RecordUserEvent(r, session.UserID, tools.EVENT_MFA_ENABLED, "")
 
 	w.WriteHeader(http.StatusNoContent)
 }
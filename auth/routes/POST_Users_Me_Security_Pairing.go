@@ -0,0 +1,55 @@
+package routes
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"dsoob/backend/tools"
+)
+
+func POST_Users_Me_Security_Pairing(w http.ResponseWriter, r *http.Request) {
+
+	session := tools.GetSession(r)
+	if !tools.UseElevation(w, r, tools.ELEVATION_SESSIONS) {
+		return
+	}
+
+	var Body struct {
+		Code string `json:"code" validate:"required,pairingcode"`
+	}
+	if !tools.BindJSON(w, r, &Body) {
+		return
+	}
+
+	// Approve Pairing Request
+	var (
+		DeviceIPAddress string
+		DeviceUserAgent string
+	)
+	err := tools.Database.QueryRowContext(r.Context(),
+		`UPDATE user_pairing SET
+			user_id = ?
+		WHERE code = UPPER(?) AND user_id IS NULL AND expires > CURRENT_TIMESTAMP
+		RETURNING device_ip_address, device_user_agent`,
+		session.UserID,
+		Body.Code,
+	).Scan(
+		&DeviceIPAddress,
+		&DeviceUserAgent,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		tools.SendClientError(w, r, tools.ERROR_UNKNOWN_PAIRING)
+		return
+	}
+	if err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	}
+
+	// Return Results
+	tools.SendJSON(w, r, http.StatusOK, map[string]any{
+		"location": tools.LookupLocation(DeviceIPAddress),
+		"browser":  tools.LookupBrowser(DeviceUserAgent),
+	})
+}
@@ -0,0 +1,79 @@
+package routes
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"dsoob/backend/tools"
+)
+
+func POST_Users_Me_Security_Tokens(w http.ResponseWriter, r *http.Request) {
+
+	session := tools.GetSession(r)
+	if !tools.UseElevation(w, r, tools.ELEVATION_TOKENS) {
+		return
+	}
+
+	var Body struct {
+		Name          string   `json:"name" validate:"required,displayname"`
+		Scopes        []string `json:"scopes" validate:"min=1,max=16,dive,scope"`
+		ExpiresInDays int      `json:"expires_in_days" validate:"omitempty,min=1,max=365"`
+	}
+	if !tools.BindJSON(w, r, &Body) {
+		return
+	}
+	slices.Sort(Body.Scopes)
+	Body.Scopes = slices.Compact(Body.Scopes)
+
+	// Check Token Limit
+	var TokenCount int
+	if err := tools.Database.QueryRowContext(r.Context(),
+		"SELECT COUNT(*) FROM user_token WHERE user_id = ?",
+		session.UserID,
+	).Scan(
+		&TokenCount,
+	); err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	}
+	if TokenCount >= tools.PERSONAL_TOKEN_LIMIT {
+		tools.SendClientError(w, r, tools.ERROR_TOKEN_LIMIT)
+		return
+	}
+
+	// Create Token
+	var (
+		TokenID      = tools.GenerateSnowflake()
+		TokenSecret  = tools.GenerateTokenString()
+		TokenExpires *time.Time
+	)
+	if Body.ExpiresInDays > 0 {
+		t := time.Now().Add(time.Duration(Body.ExpiresInDays) * 24 * time.Hour)
+		TokenExpires = &t
+	}
+	if _, err := tools.Database.ExecContext(r.Context(),
+		"INSERT INTO user_token (id, expires, user_id, name, token, scopes) VALUES (?, ?, ?, ?, ?, ?)",
+		TokenID,
+		TokenExpires,
+		session.UserID,
+		Body.Name,
+		TokenSecret,
+		strings.Join(Body.Scopes, tools.ARRAY_DELIMITER),
+	); err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	}
+
+	// Return Results
+	// 	The token is only ever shown once, afterwards it must be recreated
+	tools.SendJSON(w, r, http.StatusOK, map[string]any{
+		"id":      TokenID,
+		"expires": TokenExpires,
+		"name":    Body.Name,
+		"scopes":  Body.Scopes,
+		"prefix":  tools.TOKEN_PREFIX_PERSONAL,
+		"token":   TokenSecret,
+	})
+}
@@ -0,0 +1,45 @@
+package routes
+
+import (
+	"dsoob/backend/tools"
+	"net/http"
+)
+
+func POST_Users_Me_Settings_History_ID(w http.ResponseWriter, r *http.Request) {
+	session := tools.GetSession(r)
+	ok, namespace := tools.GetSettingsNamespace(w, r)
+	if !ok {
+		return
+	}
+	ok, version := tools.GetSnowflake(w, r)
+	if !ok {
+		return
+	}
+
+	// Fetch Version
+	raw, err := tools.SettingsReadVersion(session.UserID, namespace, version)
+	if err == tools.ErrStorageFileNotFound {
+		tools.SendClientError(w, r, tools.ERROR_UNKNOWN_SETTINGS_VERSION)
+		return
+	}
+	if err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	}
+
+	// Restore Version
+	// 	The current settings are kept in the history so a restore can be undone
+	storedHash, err := tools.SettingsWrite(session.UserID, namespace, raw, r.Header.Get("If-Match"))
+	if err == tools.ErrSettingsPreconditionFailed {
+		tools.SendClientError(w, r, tools.ERROR_SETTINGS_MODIFIED)
+		return
+	}
+	if err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	}
+
+	// Return Results
+	w.Header().Set("ETag", storedHash)
+	w.WriteHeader(http.StatusNoContent)
+}
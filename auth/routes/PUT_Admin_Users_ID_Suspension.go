@@ -0,0 +1,84 @@
+package routes
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	"dsoob/backend/tools"
+)
+
+func PUT_Admin_Users_ID_Suspension(w http.ResponseWriter, r *http.Request) {
+
+	session := tools.GetSession(r)
+	ok, userID := tools.GetSnowflake(w, r)
+	if !ok {
+		return
+	}
+
+	var Body struct {
+		Reason string `json:"reason" validate:"required,description"`
+	}
+	if !tools.BindJSON(w, r, &Body) {
+		return
+	}
+
+	tx, err := tools.Database.BeginTx(r.Context(), nil)
+	if err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	}
+	defer tx.Rollback()
+
+	// Suspend User
+	var UserEmailAddress string
+	err = tx.QueryRowContext(r.Context(),
+		`UPDATE user SET
+			updated          = CURRENT_TIMESTAMP,
+			suspended_at     = ?,
+			suspended_reason = ?
+		WHERE id = ?
+		RETURNING email_address`,
+		time.Now(),
+		Body.Reason,
+		userID,
+	).Scan(
+		&UserEmailAddress,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		tools.SendClientError(w, r, tools.ERROR_UNKNOWN_USER)
+		return
+	}
+	if err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	}
+
+	// Logout User
+	SessionIDs, err := tools.DeleteUserSessions(r.Context(), tx, userID, 0)
+	if err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		tools.SendServerError(w, r, err)
+		return
+	}
+	tools.RevokeAccessTokens(SessionIDs...)
+	tools.LoggerAdmin.Data(tools.INFO, "User Suspended", map[string]any{
+		"admin_id": session.UserID,
+		"user_id":  userID,
+		"reason":   Body.Reason,
+	})
+
+	// Notify User
+	go tools.EmailNotifyUserSuspended(UserEmailAddress,
+		tools.LocalsNotifyUserSuspended{
+			Reason: Body.Reason,
+		},
+	)
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -1,18 +1,17 @@
 package routes
 
 import (
-	"crypto/sha256"
 	"dsoob/backend/tools"
-	"fmt"
 	"io"
 	"net/http"
-	"os"
-	"path"
-	"strconv"
 )
 
 func PUT_Users_Me_Settings(w http.ResponseWriter, r *http.Request) {
 	session := tools.GetSession(r)
+	ok, namespace := tools.GetSettingsNamespace(w, r)
+	if !ok {
+		return
+	}
 
 	// Collect Settings
 	givenSettings, err := io.ReadAll(r.Body)
@@ -20,14 +19,20 @@ func PUT_Users_Me_Settings(w http.ResponseWriter, r *http.Request) {
 		tools.SendServerError(w, r, err)
 		return
 	}
-	givenHash := fmt.Sprintf("%x", sha256.Sum256(givenSettings))
 
 	// Store Settings
-	filepath := path.Join(
-		tools.DATA_DIRECTORY, "settings",
-		strconv.FormatInt(session.UserID, 10)+".raw",
-	)
-	if err := os.WriteFile(filepath, givenSettings, tools.FILEMODE_SECURE); err != nil {
+	// 	Clients should send the ETag they last saw so that changes
+	// 	made by another device in the meantime are not overwritten
+	givenHash, err := tools.SettingsWrite(session.UserID, namespace, givenSettings, r.Header.Get("If-Match"))
+	if err == tools.ErrSettingsPreconditionFailed {
+		tools.SendClientError(w, r, tools.ERROR_SETTINGS_MODIFIED)
+		return
+	}
+	if err == tools.ErrSettingsNamespaceLimit {
+		tools.SendClientError(w, r, tools.ERROR_SETTINGS_NAMESPACE_LIMIT)
+		return
+	}
+	if err != nil {
 		tools.SendServerError(w, r, err)
 		return
 	}
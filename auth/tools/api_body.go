@@ -8,19 +8,19 @@ import (
 	"io"
 	"net/http"
 	"regexp"
+	"slices"
 	"strings"
 
 	"github.com/go-playground/validator/v10"
 )
 
 var (
-	BodyValidator     = validator.New(validator.WithRequiredStructEnabled())
-	REGEX_USERNAME    = regexp.MustCompile("^[a-zA-Z0-9_]{3,32}$")        //
-	REGEX_PASSCODE    = regexp.MustCompile("^([0-9]{6}|[0-9ABCDEF]{8})$") //
-	REGEX_HAS_SPECIAL = regexp.MustCompile(`\P{L}`)                       // non-letter Unicode
-	REGEX_HAS_UPPER   = regexp.MustCompile(`\p{Lu}`)                      // uppercase letter (any script)
-	REGEX_HAS_LOWER   = regexp.MustCompile(`\p{Ll}`)                      // lowercase letter (any script)
-	REGEX_HAS_NUMBER  = regexp.MustCompile(`[0-9]`)                       // numbers
+	BodyValidator   = validator.New(validator.WithRequiredStructEnabled())
+	REGEX_USERNAME  = regexp.MustCompile("^[a-zA-Z0-9_]{3,32}$")        //
+	REGEX_PASSCODE  = regexp.MustCompile("^([0-9]{6}|[0-9ABCDEF]{8})$") //
+	REGEX_PAIRING   = regexp.MustCompile("^[A-HJ-NP-Z2-9]{8}$")         // see GeneratePairingCode
+	REGEX_INVITE    = regexp.MustCompile("^[A-HJ-NP-Z2-9]{12}$")        // see GenerateInviteCode
+	REGEX_NAMESPACE = regexp.MustCompile("^[a-z0-9_-]{1,32}$")          // see GetSettingsNamespace
 )
 
 func init() {
@@ -48,16 +48,28 @@ func init() {
 		return true
 	})
 
+	BodyValidator.RegisterValidation("pairingcode", func(fl validator.FieldLevel) bool {
+		str := fl.Field().String()
+		return REGEX_PAIRING.MatchString(strings.ToUpper(str))
+	})
+
+	BodyValidator.RegisterValidation("invitecode", func(fl validator.FieldLevel) bool {
+		str := fl.Field().String()
+		return REGEX_INVITE.MatchString(strings.ToUpper(str))
+	})
+
+	BodyValidator.RegisterValidation("scope", func(fl validator.FieldLevel) bool {
+		return slices.Contains(SCOPES_GRANTABLE, fl.Field().String())
+	})
+
+	BodyValidator.RegisterValidation("elevation", func(fl validator.FieldLevel) bool {
+		return slices.Contains(ELEVATION_ACTIONS, fl.Field().String())
+	})
+
 	BodyValidator.RegisterValidation("password", func(fl validator.FieldLevel) bool {
 		str := fl.Field().String()
-		// 64 characters due to bcrypt limitation
-		if len(str) < 8 || len(str) > 64 || !REGEX_HAS_SPECIAL.MatchString(str) ||
-			!REGEX_HAS_UPPER.MatchString(str) ||
-			!REGEX_HAS_LOWER.MatchString(str) ||
-			!REGEX_HAS_NUMBER.MatchString(str) {
-			return false
-		}
-		return true
+		// Strength depends on the user and is checked separately, see ValidatePasswordStrength
+		return len(str) >= 8 && len(str) <= PASSWORD_LENGTH_LIMIT
 	})
 
 	BodyValidator.RegisterValidation("username", func(fl validator.FieldLevel) bool {
@@ -18,6 +18,8 @@ var (
 	ERROR_GENERIC_NOT_FOUND           = APIError{Status: 404, Code: 0, Message: "Endpoint Not Found"}
 	ERROR_GENERIC_RATELIMIT           = APIError{Status: 429, Code: 0, Message: "Too Many Requests"}
 	ERROR_GENERIC_UNAUTHORIZED        = APIError{Status: 401, Code: 0, Message: "Unauthorized"}
+	ERROR_GENERIC_FORBIDDEN           = APIError{Status: 403, Code: 0, Message: "Missing Required Scope"}
+	ERROR_GENERIC_MISSING_PERMISSION  = APIError{Status: 403, Code: 0, Message: "Missing Required Permission"}
 	ERROR_GENERIC_METHOD_NOT_ALLOWED  = APIError{Status: 405, Code: 0, Message: "Method Not Allowed"}
 	ERROR_GENERIC_GZIP_REQUIRED       = APIError{Status: 400, Code: 0, Message: "Support for GZIP is required for this endpoint"}
 	ERROR_BODY_EMPTY                  = APIError{Status: 411, Code: 0, Message: "Request Body is Empty"}
@@ -28,6 +30,12 @@ var (
 	ERROR_UNKNOWN_USER                = APIError{Status: 404, Code: 1020, Message: "Unknown User"}
 	ERROR_UNKNOWN_SESSION             = APIError{Status: 404, Code: 1040, Message: "Unknown Session"}
 	ERROR_UNKNOWN_IMAGE               = APIError{Status: 404, Code: 1050, Message: "Unknown Image"}
+	ERROR_UNKNOWN_PAIRING             = APIError{Status: 404, Code: 1060, Message: "Unknown Pairing Code"}
+	ERROR_UNKNOWN_APPLICATION         = APIError{Status: 404, Code: 1070, Message: "Unknown Application"}
+	ERROR_UNKNOWN_TOKEN               = APIError{Status: 404, Code: 1080, Message: "Unknown Token"}
+	ERROR_UNKNOWN_EXPORT              = APIError{Status: 404, Code: 1090, Message: "Unknown Export"}
+	ERROR_UNKNOWN_SETTINGS_VERSION    = APIError{Status: 404, Code: 1100, Message: "Unknown Settings Version"}
+	ERROR_UNKNOWN_INVITE              = APIError{Status: 404, Code: 1110, Message: "Unknown Invite"}
 	ERROR_IMAGE_UNSUPPORTED           = APIError{Status: 400, Code: 2010, Message: "Unsupported Image Format (Supports: WEBP, GIF, JPEG, PNG)"}
 	ERROR_IMAGE_MALFORMED             = APIError{Status: 400, Code: 2020, Message: "Invalid or Malformed Image Data"}
 	ERROR_LOGIN_INCORRECT             = APIError{Status: 401, Code: 4010, Message: "Incorrect Email or Password"}
@@ -36,6 +44,18 @@ var (
 	ERROR_LOGIN_PASSWORD_ALREADY_USED = APIError{Status: 400, Code: 4040, Message: "Password Already Used"}
 	ERROR_SIGNUP_DUPLICATE_USERNAME   = APIError{Status: 409, Code: 4050, Message: "Username is already in use"}
 	ERROR_SIGNUP_DUPLICATE_EMAIL      = APIError{Status: 409, Code: 4060, Message: "Email Address is already in use"}
+	ERROR_LOGIN_PAIRING_PENDING       = APIError{Status: 202, Code: 4070, Message: "Awaiting Approval from an Existing Device"}
+	ERROR_LOGIN_ACCOUNT_SUSPENDED     = APIError{Status: 403, Code: 4080, Message: "Account Suspended"}
+	ERROR_USERNAME_COOLDOWN           = APIError{Status: 429, Code: 4090, Message: "Username was Changed Recently"}
+	ERROR_LOGIN_PASSWORD_BREACHED     = APIError{Status: 400, Code: 4100, Message: "Password has Appeared in a Data Breach, Please Choose Another"}
+	ERROR_LOGIN_PASSWORD_WEAK         = APIError{Status: 400, Code: 4110, Message: "Password is too Easy to Guess"}
+	ERROR_LOGIN_PROOF_REQUIRED        = APIError{Status: 428, Code: 4120, Message: "Proof of Work Required"}
+	ERROR_LOGIN_PROOF_INVALID         = APIError{Status: 403, Code: 4130, Message: "Proof of Work Invalid or Expired"}
+	ERROR_SIGNUP_CLOSED               = APIError{Status: 403, Code: 4140, Message: "Registration is Closed"}
+	ERROR_SIGNUP_INVITE_REQUIRED      = APIError{Status: 403, Code: 4150, Message: "Invite Code Required"}
+	ERROR_SIGNUP_INVITE_INVALID       = APIError{Status: 403, Code: 4160, Message: "Invite Code is Invalid or Expired"}
+	ERROR_SIGNUP_EMAIL_INVALID        = APIError{Status: 400, Code: 4170, Message: "Email Address is Invalid"}
+	ERROR_SIGNUP_EMAIL_DISPOSABLE     = APIError{Status: 400, Code: 4180, Message: "Disposable Email Addresses are not Allowed"}
 	ERROR_MFA_EMAIL_SENT              = APIError{Status: 403, Code: 5010, Message: "Email Sent"}
 	ERROR_MFA_EMAIL_ALREADY_VERIFIED  = APIError{Status: 400, Code: 5020, Message: "Email Address already Verified"}
 	ERROR_MFA_PASSCODE_REQUIRED       = APIError{Status: 403, Code: 5030, Message: "Authenticator Passcode Required"}
@@ -47,6 +67,18 @@ var (
 	ERROR_MFA_DISABLED                = APIError{Status: 412, Code: 5090, Message: "MFA is Disabled"}
 	ERROR_MFA_SETUP_ALREADY           = APIError{Status: 400, Code: 5100, Message: "MFA is Already Setup"}
 	ERROR_MFA_SETUP_NOT_INITIALIZED   = APIError{Status: 412, Code: 5110, Message: "MFA Setup not Started"}
+	ERROR_MFA_REMOVAL_PENDING         = APIError{Status: 409, Code: 5120, Message: "MFA Removal Already Requested"}
+	ERROR_MFA_PASSCODE_EXPIRED        = APIError{Status: 401, Code: 5130, Message: "Passcode Expired, Please Request a New One"}
+	ERROR_MFA_ESCALATION_INSUFFICIENT = APIError{Status: 403, Code: 5140, Message: "Escalation does not Permit this Action"}
+	ERROR_APPLICATION_LIMIT           = APIError{Status: 400, Code: 6010, Message: "Maximum Number of Applications Reached"}
+	ERROR_TOKEN_LIMIT                 = APIError{Status: 400, Code: 6020, Message: "Maximum Number of Tokens Reached"}
+	ERROR_EXPORT_ALREADY_REQUESTED    = APIError{Status: 409, Code: 6030, Message: "Data Export Already Requested"}
+	ERROR_INVITE_LIMIT                = APIError{Status: 400, Code: 6040, Message: "Maximum Number of Invites Reached"}
+	ERROR_INVITE_DISABLED             = APIError{Status: 403, Code: 6050, Message: "Invites can only be Created by Administrators"}
+	ERROR_SETTINGS_MODIFIED           = APIError{Status: 412, Code: 7010, Message: "Settings were Modified by Another Device"}
+	ERROR_SETTINGS_SESSION_REQUIRED   = APIError{Status: 400, Code: 7020, Message: "Session Settings are only available to User Sessions"}
+	ERROR_SETTINGS_NAMESPACE_LIMIT    = APIError{Status: 400, Code: 7030, Message: "Maximum Number of Settings Namespaces Reached"}
+	ERROR_ADMIN_TARGET_PROTECTED      = APIError{Status: 403, Code: 8010, Message: "Cannot Modify a User with Equal or Greater Permissions"}
 )
 
 // Cancel Request and Respond with an API Error
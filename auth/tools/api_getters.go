@@ -28,6 +28,29 @@ func GetSnowflake(w http.ResponseWriter, r *http.Request) (bool, int64) {
 	return true, v
 }
 
+// Get Settings Namespace from Request Path.
+// Returns the empty namespace if namespace is not present in the http handler (e.g. '/settings/{namespace}'),
+// 'default' is an alias for the empty namespace and '@session' for the namespace of the current session
+func GetSettingsNamespace(w http.ResponseWriter, r *http.Request) (bool, string) {
+	switch v := r.PathValue("namespace"); v {
+	case "", "default":
+		return true, ""
+	case "@session":
+		session := GetSession(r)
+		if session.SessionID == 0 {
+			SendClientError(w, r, ERROR_SETTINGS_SESSION_REQUIRED)
+			return false, ""
+		}
+		return true, "@" + strconv.FormatInt(session.SessionID, 10)
+	default:
+		if !REGEX_NAMESPACE.MatchString(v) {
+			SendClientError(w, r, ERROR_BODY_INVALID_FIELD)
+			return false, ""
+		}
+		return true, v
+	}
+}
+
 // Get IP Address of Incoming Client
 func GetRemoteIP(r *http.Request) string {
 	remoteAddr, _, err := net.SplitHostPort(r.RemoteAddr)
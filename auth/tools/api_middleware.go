@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"errors"
 	"net/http"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
@@ -12,9 +13,11 @@ import (
 )
 
 type SessionData struct {
-	SessionID int64 // Relevant Session ID
-	UserID    int64 // Relevant User ID
-	Elevated  bool  // Relevant Session Elevated?
+	SessionID     int64    // Relevant Session ID
+	UserID        int64    // Relevant User ID
+	ApplicationID int64    // Relevant Application ID (Bot Sessions Only)
+	TokenID       int64    // Relevant Personal Access Token ID (Token Sessions Only)
+	Scopes        []string // Granted Scopes, nil if unrestricted
 }
 
 type ratelimitEntry struct {
@@ -96,6 +99,23 @@ func NewRatelimit(limit int64, period time.Duration) MiddlewareFunc {
 	}
 }
 
+// Protect Server against Bots by Requiring a solved Proof of Work Challenge, if enabled
+func UseProofOfWork(w http.ResponseWriter, r *http.Request) bool {
+	if POW_DIFFICULTY <= 0 {
+		return true
+	}
+	h := strings.TrimSpace(r.Header.Get(POW_HEADER))
+	if h == "" {
+		SendClientError(w, r, ERROR_LOGIN_PROOF_REQUIRED)
+		return false
+	}
+	if !ProofVerify(GetRemoteIP(r), h) {
+		SendClientError(w, r, ERROR_LOGIN_PROOF_INVALID)
+		return false
+	}
+	return true
+}
+
 // Retrieve User or Application Session from Request
 func UseSession(w http.ResponseWriter, r *http.Request) bool {
 
@@ -105,17 +125,40 @@ func UseSession(w http.ResponseWriter, r *http.Request) bool {
 	// User Prefix
 	case strings.HasPrefix(h, TOKEN_PREFIX_USER):
 
-		// Retrieve User Session
-		var session SessionData
-		var sessionElevatedUntil time.Time
+		// Verify Access Token
+		// 	Access Tokens are signed and short-lived so no database lookup is required,
+		// 	clients are expected to use their refresh token once it expires. Suspending or
+		// 	deleting an account deletes its sessions which in turn revokes their access tokens
+		ok, claims := ParseAccessToken(strings.TrimPrefix(h, TOKEN_PREFIX_USER))
+		if !ok {
+			SendClientError(w, r, ERROR_GENERIC_UNAUTHORIZED)
+			return false
+		}
+		session := SessionData{
+			SessionID: claims.SessionID,
+			UserID:    claims.UserID,
+		}
+
+		// Apply Session to Request Context
+		ctxWithSession := context.WithValue(r.Context(), SESSION_KEY, &session)
+		*r = *r.WithContext(ctxWithSession)
 
+	// Bot Prefix
+	case strings.HasPrefix(h, TOKEN_PREFIX_BOT):
+
+		// Retrieve Application
+		// 	Bots act on behalf of their owner but are restricted to their granted scopes
+		var session SessionData
+		var sessionScopesRAW string
 		err := Database.QueryRowContext(r.Context(),
-			"SELECT id, user_id, elevated_until FROM user_session WHERE token = ?",
-			strings.TrimPrefix(h, TOKEN_PREFIX_USER),
+			`SELECT a.id, a.owner_id, a.scopes FROM application a
+			JOIN user u ON u.id = a.owner_id
+			WHERE a.secret = ? AND u.suspended_at IS NULL AND u.deleted_at IS NULL`,
+			strings.TrimPrefix(h, TOKEN_PREFIX_BOT),
 		).Scan(
-			&session.SessionID,
+			&session.ApplicationID,
 			&session.UserID,
-			&sessionElevatedUntil,
+			&sessionScopesRAW,
 		)
 		if errors.Is(err, sql.ErrNoRows) {
 			SendClientError(w, r, ERROR_GENERIC_UNAUTHORIZED)
@@ -125,10 +168,44 @@ func UseSession(w http.ResponseWriter, r *http.Request) bool {
 			SendServerError(w, r, err)
 			return false
 		}
+		session.Scopes = []string{}
+		if sessionScopesRAW != "" {
+			session.Scopes = strings.Split(sessionScopesRAW, ARRAY_DELIMITER)
+		}
+
+		// Apply Session to Request Context
+		ctxWithSession := context.WithValue(r.Context(), SESSION_KEY, &session)
+		*r = *r.WithContext(ctxWithSession)
 
-		// Additional Checks
-		if time.Now().Before(sessionElevatedUntil) {
-			session.Elevated = true
+	// Personal Access Token Prefix
+	case strings.HasPrefix(h, TOKEN_PREFIX_PERSONAL):
+
+		// Retrieve Personal Access Token
+		// 	Tokens are intended for scripts and are kept apart from device sessions
+		var session SessionData
+		var sessionScopesRAW string
+		err := Database.QueryRowContext(r.Context(),
+			`SELECT t.id, t.user_id, t.scopes FROM user_token t
+			JOIN user u ON u.id = t.user_id
+			WHERE t.token = ? AND (t.expires IS NULL OR t.expires > CURRENT_TIMESTAMP)
+			AND u.suspended_at IS NULL AND u.deleted_at IS NULL`,
+			strings.TrimPrefix(h, TOKEN_PREFIX_PERSONAL),
+		).Scan(
+			&session.TokenID,
+			&session.UserID,
+			&sessionScopesRAW,
+		)
+		if errors.Is(err, sql.ErrNoRows) {
+			SendClientError(w, r, ERROR_GENERIC_UNAUTHORIZED)
+			return false
+		}
+		if err != nil {
+			SendServerError(w, r, err)
+			return false
+		}
+		session.Scopes = []string{}
+		if sessionScopesRAW != "" {
+			session.Scopes = strings.Split(sessionScopesRAW, ARRAY_DELIMITER)
 		}
 
 		// Apply Session to Request Context
@@ -144,3 +221,85 @@ func UseSession(w http.ResponseWriter, r *http.Request) bool {
 
 	return true
 }
+
+// Restrict Endpoint to Sessions granted the given Scope.
+// Expects UseSession to be earlier in the http handler chain
+func NewScope(scope string) MiddlewareFunc {
+	return func(w http.ResponseWriter, r *http.Request) bool {
+		session := GetSession(r)
+		if session.Scopes != nil && !slices.Contains(session.Scopes, scope) {
+			SendClientError(w, r, ERROR_GENERIC_FORBIDDEN)
+			return false
+		}
+		return true
+	}
+}
+
+// Restrict Endpoint to Users holding every given Permission.
+// Expects UseSession to be earlier in the http handler chain
+func NewPermission(permission int64) MiddlewareFunc {
+	return func(w http.ResponseWriter, r *http.Request) bool {
+		session := GetSession(r)
+
+		// Permissions are looked up on every request so
+		// that they can be revoked without any delay
+		var UserPermissions int64
+		err := Database.QueryRowContext(r.Context(),
+			"SELECT permissions FROM user WHERE id = ? AND suspended_at IS NULL AND deleted_at IS NULL",
+			session.UserID,
+		).Scan(
+			&UserPermissions,
+		)
+		if errors.Is(err, sql.ErrNoRows) {
+			SendClientError(w, r, ERROR_GENERIC_UNAUTHORIZED)
+			return false
+		}
+		if err != nil {
+			SendServerError(w, r, err)
+			return false
+		}
+		if UserPermissions&permission != permission {
+			SendClientError(w, r, ERROR_GENERIC_MISSING_PERMISSION)
+			return false
+		}
+		return true
+	}
+}
+
+// Restrict Endpoint to Target Users holding a strict subset of the acting User's Permissions,
+// prevents moderators from acting on themselves or on users with equal or greater privileges.
+// Expects UseSession and NewPermission to be earlier in the http handler chain
+func UseAdminTarget(w http.ResponseWriter, r *http.Request) bool {
+	session := GetSession(r)
+	ok, targetID := GetSnowflake(w, r)
+	if !ok {
+		return false
+	}
+	if targetID == session.UserID {
+		SendClientError(w, r, ERROR_ADMIN_TARGET_PROTECTED)
+		return false
+	}
+
+	var UserPermissions, TargetPermissions int64
+	err := Database.QueryRowContext(r.Context(),
+		"SELECT (SELECT permissions FROM user WHERE id = ?), permissions FROM user WHERE id = ?",
+		session.UserID,
+		targetID,
+	).Scan(
+		&UserPermissions,
+		&TargetPermissions,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		SendClientError(w, r, ERROR_UNKNOWN_USER)
+		return false
+	}
+	if err != nil {
+		SendServerError(w, r, err)
+		return false
+	}
+	if TargetPermissions&^UserPermissions != 0 || TargetPermissions == UserPermissions {
+		SendClientError(w, r, ERROR_ADMIN_TARGET_PROTECTED)
+		return false
+	}
+	return true
+}
@@ -51,6 +51,13 @@ func EndpointImageUpload(w http.ResponseWriter, r *http.Request, column string,
 					"error": err.Error(),
 				})
 			}
+			original := ImageOriginalPath(options, session.UserID, UploadHash)
+			if err := StoragePrivateDelete(original); err != nil {
+				LoggerStorage.Data(ERROR, "Unable to delete leftover original image", map[string]any{
+					"path":  original,
+					"error": err.Error(),
+				})
+			}
 		}
 
 		// Delete previous images (if any)
@@ -62,6 +69,13 @@ func EndpointImageUpload(w http.ResponseWriter, r *http.Request, column string,
 					"error": err.Error(),
 				})
 			}
+			original := ImageOriginalPath(options, session.UserID, *PreviousHash)
+			if err := StoragePrivateDelete(original); err != nil {
+				LoggerStorage.Data(ERROR, "Failed to delete previous original image", map[string]any{
+					"path":  original,
+					"error": err.Error(),
+				})
+			}
 		}
 	}()
 
@@ -106,62 +120,70 @@ func EndpointImageUpload(w http.ResponseWriter, r *http.Request, column string,
 	w.WriteHeader(http.StatusNoContent)
 }
 
-func EndpointImageDelete(w http.ResponseWriter, r *http.Request, column string, options ImageOptions) {
+// Remove an Image from the given User, returns true if the image was deleted
+func EndpointImageDelete(w http.ResponseWriter, r *http.Request, userID int64, column string, options ImageOptions) bool {
 
 	var BannerHash *string
-	session := GetSession(r)
 
 	// Update Account
 	tx, err := Database.BeginTx(r.Context(), nil)
 	if err != nil {
 		SendServerError(w, r, err)
-		return
+		return false
 	}
 	defer tx.Rollback()
 
 	err = tx.QueryRowContext(r.Context(),
 		"SELECT "+column+" FROM user WHERE id = ?",
-		session.UserID,
+		userID,
 	).Scan(
 		&BannerHash,
 	)
 	if errors.Is(err, sql.ErrNoRows) {
 		SendClientError(w, r, ERROR_UNKNOWN_USER)
-		return
+		return false
 	}
 	if err != nil {
 		SendServerError(w, r, err)
-		return
+		return false
 	}
 
 	_, err = tx.ExecContext(r.Context(),
 		"UPDATE user SET updated = CURRENT_TIMESTAMP, "+column+" = NULL WHERE id = ?",
-		session.UserID,
+		userID,
 	)
 	if err != nil {
 		SendServerError(w, r, err)
-		return
+		return false
 	}
 
 	if err := tx.Commit(); err != nil {
 		SendServerError(w, r, err)
-		return
+		return false
 	}
 
 	// Delete Previous Image (if any)
 	if BannerHash == nil {
 		SendClientError(w, r, ERROR_UNKNOWN_IMAGE)
-		return
+		return false
 	}
 	go func() {
-		paths := ImagePaths(options, session.UserID, *BannerHash)
+		paths := ImagePaths(options, userID, *BannerHash)
 		if err := StoragePublicDelete(paths...); err != nil {
 			LoggerStorage.Data(ERROR, "Failed to delete images", map[string]any{
 				"paths": paths,
 				"error": err.Error(),
 			})
 		}
+		original := ImageOriginalPath(options, userID, *BannerHash)
+		if err := StoragePrivateDelete(original); err != nil {
+			LoggerStorage.Data(ERROR, "Failed to delete original image", map[string]any{
+				"path":  original,
+				"error": err.Error(),
+			})
+		}
 	}()
 
 	w.WriteHeader(http.StatusNoContent)
+	return true
 }
@@ -0,0 +1,80 @@
+package tools
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/binary"
+	"errors"
+	"io/fs"
+	"os"
+	"path"
+	"sync"
+	"time"
+)
+
+// Breached Passwords are checked against a bloom filter built from a list of SHA-1 hashes (see DebugPasswordUpdateBreached),
+// the filter is too large to embed so it is read from the private directory at startup. Each password is hashed once and
+// the digest is split into two halves which are combined to derive every bit position, false positives are possible
+// but rare while false negatives are not. Without a filter every password is accepted.
+
+var (
+	breachedBits   []byte = nil
+	breachedSize   uint64 = 0
+	breachedHashes uint32 = 0
+)
+
+func BreachedSetup(stop context.Context, await *sync.WaitGroup) {
+	t := time.Now()
+
+	// Read Filter
+	filter, err := os.ReadFile(path.Join(DATA_DIRECTORY, "private", BREACHED_PASSWORDS_FILE))
+	if errors.Is(err, fs.ErrNotExist) {
+		LoggerPassword.Log(WARN, "Breached Password Filter not found, passwords will not be checked")
+		return
+	}
+	if err != nil {
+		LoggerPassword.Log(FATAL, "Failed to Read Filter: %s", err)
+		return
+	}
+	if len(filter) < 12 {
+		LoggerPassword.Log(FATAL, "Invalid Filter Header")
+		return
+	}
+	size := binary.LittleEndian.Uint64(filter[0:8])
+	hashes := binary.LittleEndian.Uint32(filter[8:12])
+	if size == 0 || hashes == 0 || uint64(len(filter)-12) != (size+7)/8 {
+		LoggerPassword.Log(FATAL, "Invalid Filter Size")
+		return
+	}
+	breachedBits, breachedSize, breachedHashes = filter[12:], size, hashes
+
+	// Debugging
+	LoggerPassword.Log(INFO, "Parsed %d Bits with %d Hashes", breachedSize, breachedHashes)
+	LoggerPassword.Log(INFO, "Ready in %s", time.Since(t))
+}
+
+// Calculate the Bit Positions of the given SHA-1 Digest within a filter of the given size
+func BreachedPositions(digest [sha1.Size]byte, size uint64, hashes uint32) []uint64 {
+	var (
+		h1        = binary.LittleEndian.Uint64(digest[0:8])
+		h2        = binary.LittleEndian.Uint64(digest[8:16]) | 1
+		positions = make([]uint64, hashes)
+	)
+	for i := range positions {
+		positions[i] = (h1 + uint64(i)*h2) % size
+	}
+	return positions
+}
+
+// Check whether the given Password appears in the Breached Password Filter
+func PasswordBreached(givenPassword string) bool {
+	if breachedBits == nil {
+		return false
+	}
+	for _, p := range BreachedPositions(sha1.Sum([]byte(givenPassword)), breachedSize, breachedHashes) {
+		if breachedBits[p/8]&(1<<(p%8)) == 0 {
+			return false
+		}
+	}
+	return true
+}
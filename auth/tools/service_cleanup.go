@@ -0,0 +1,278 @@
+package tools
+
+import (
+	"context"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+func CleanupSetup(stop context.Context, await *sync.WaitGroup) {
+	t := time.Now()
+
+	// Cleanup Logic
+	await.Add(1)
+	go func() {
+		defer await.Done()
+		interval := time.NewTicker(CLEANUP_INTERVAL)
+		defer interval.Stop()
+		for {
+			cleanupDeletedUsers()
+			cleanupRemovedMFA()
+			cleanupExpiredExports()
+			cleanupExpiredRows()
+			cleanupSessionSettings()
+			select {
+			case <-stop.Done():
+				LoggerCleanup.Log(INFO, "Closed")
+				return
+			case <-interval.C:
+			}
+		}
+	}()
+	LoggerCleanup.Log(INFO, "Ready in %s", time.Since(t))
+}
+
+// Permanently delete accounts whose deletion grace period has ended
+func cleanupDeletedUsers() {
+	ctx, cancel := context.WithTimeout(context.Background(), TIMEOUT_CONTEXT)
+	defer cancel()
+
+	rows, err := Database.QueryContext(ctx,
+		"DELETE FROM user WHERE deleted_at < ? RETURNING id, email_address, avatar_hash, banner_hash",
+		time.Now().AddDate(0, 0, -DELETE_GRACE_DAYS),
+	)
+	if err != nil {
+		LoggerCleanup.Log(ERROR, "Cannot delete users: %s", err)
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			UserID           int64
+			UserEmailAddress string
+			UserAvatarHash   *string
+			UserBannerHash   *string
+		)
+		if err := rows.Scan(&UserID, &UserEmailAddress, &UserAvatarHash, &UserBannerHash); err != nil {
+			LoggerCleanup.Log(ERROR, "Cannot scan user: %s", err)
+			return
+		}
+
+		// Delete Any Account Images
+		var imagePaths = []string{}
+		if UserAvatarHash != nil {
+			imagePaths = append(imagePaths,
+				ImagePaths(ImageOptionsAvatars, UserID, *UserAvatarHash)...,
+			)
+		}
+		if UserBannerHash != nil {
+			imagePaths = append(imagePaths,
+				ImagePaths(ImageOptionsBanners, UserID, *UserBannerHash)...,
+			)
+		}
+		if err := StoragePublicDelete(imagePaths...); err != nil {
+			LoggerStorage.Data(ERROR, "Failed to Delete Account Images", map[string]any{
+				"paths": imagePaths,
+				"error": err.Error(),
+			})
+		}
+
+		// Delete Any Original Images
+		// 	An empty hash resolves to the directory holding every original of the user
+		originalPaths := []string{
+			ImageOriginalPath(ImageOptionsAvatars, UserID, ""),
+			ImageOriginalPath(ImageOptionsBanners, UserID, ""),
+		}
+		if err := StoragePrivateDelete(originalPaths...); err != nil {
+			LoggerStorage.Data(ERROR, "Failed to Delete Account Original Images", map[string]any{
+				"paths": originalPaths,
+				"error": err.Error(),
+			})
+		}
+
+		// Delete Any Data Exports
+		exportPath := path.Dir(ExportPath(UserID, 0))
+		if err := StoragePrivateDelete(exportPath); err != nil {
+			LoggerStorage.Data(ERROR, "Failed to Delete Account Exports", map[string]any{
+				"path":  exportPath,
+				"error": err.Error(),
+			})
+		}
+
+		// Delete Account Settings
+		if err := SettingsDeleteAll(UserID); err != nil {
+			LoggerStorage.Data(ERROR, "Failed to Delete Account Settings", map[string]any{
+				"user_id": UserID,
+				"error":   err.Error(),
+			})
+		}
+
+		// Notify User
+		go EmailNotifyUserDeleted(UserEmailAddress,
+			LocalsNotifyUserDeleted{
+				Content: "account",
+				Reason:  "User Request",
+			},
+		)
+		LoggerCleanup.Log(INFO, "Deleted User %d", UserID)
+	}
+	if err := rows.Err(); err != nil {
+		LoggerCleanup.Log(ERROR, "Cannot delete users: %s", err)
+	}
+}
+
+// Remove MFA from accounts whose recovery period has ended without being cancelled
+func cleanupRemovedMFA() {
+	ctx, cancel := context.WithTimeout(context.Background(), TIMEOUT_CONTEXT)
+	defer cancel()
+
+	rows, err := Database.QueryContext(ctx,
+		`UPDATE user SET
+			updated 		  = CURRENT_TIMESTAMP,
+			mfa_enabled 	  = FALSE,
+			mfa_secret 		  = NULL,
+			mfa_codes 		  = '',
+			mfa_codes_used 	  = 0,
+			mfa_removal_at 	  = NULL,
+			token_mfa_removal = NULL
+		WHERE mfa_enabled = TRUE AND mfa_removal_at < ?
+		RETURNING id, email_address`,
+		time.Now().AddDate(0, 0, -MFA_REMOVAL_DAYS),
+	)
+	if err != nil {
+		LoggerCleanup.Log(ERROR, "Cannot remove MFA: %s", err)
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			UserID           int64
+			UserEmailAddress string
+		)
+		if err := rows.Scan(&UserID, &UserEmailAddress); err != nil {
+			LoggerCleanup.Log(ERROR, "Cannot scan user: %s", err)
+			return
+		}
+		go EmailNotifyUserDeleted(UserEmailAddress,
+			LocalsNotifyUserDeleted{
+				Content: "two-factor authentication",
+				Reason:  "Account Recovery. Please request a new password reset using 'Forgot Password?' on the login page",
+			},
+		)
+		LoggerCleanup.Log(INFO, "Removed MFA from User %d", UserID)
+	}
+	if err := rows.Err(); err != nil {
+		LoggerCleanup.Log(ERROR, "Cannot remove MFA: %s", err)
+	}
+}
+
+// Remove data exports which can no longer be downloaded
+func cleanupExpiredExports() {
+	ctx, cancel := context.WithTimeout(context.Background(), TIMEOUT_CONTEXT)
+	defer cancel()
+
+	rows, err := Database.QueryContext(ctx,
+		"DELETE FROM user_export WHERE expires < ? RETURNING id, user_id",
+		time.Now(),
+	)
+	if err != nil {
+		LoggerCleanup.Log(ERROR, "Cannot cleanup exports: %s", err)
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var ExportID, ExportUserID int64
+		if err := rows.Scan(&ExportID, &ExportUserID); err != nil {
+			LoggerCleanup.Log(ERROR, "Cannot scan export: %s", err)
+			return
+		}
+		if err := StoragePrivateDelete(ExportPath(ExportUserID, ExportID)); err != nil {
+			LoggerStorage.Data(ERROR, "Failed to Delete Export", map[string]any{
+				"export_id": ExportID,
+				"error":     err.Error(),
+			})
+		}
+	}
+	if err := rows.Err(); err != nil {
+		LoggerCleanup.Log(ERROR, "Cannot cleanup exports: %s", err)
+	}
+}
+
+// Remove rows which can no longer be used by anyone
+func cleanupExpiredRows() {
+	ctx, cancel := context.WithTimeout(context.Background(), TIMEOUT_CONTEXT)
+	defer cancel()
+
+	for _, job := range []struct {
+		Name  string
+		Query string
+		Args  []any
+	}{
+		{"Pairing Requests", "DELETE FROM user_pairing WHERE expires < CURRENT_TIMESTAMP", nil},
+		{"Challenges", "DELETE FROM user_challenge WHERE expires < ?", []any{time.Now()}},
+		{"Invites", "DELETE FROM user_invite WHERE expires < ? OR uses >= uses_limit", []any{time.Now()}},
+		{"Sessions", "DELETE FROM user_session WHERE updated < ?", []any{time.Now().Add(-TOKEN_LIFETIME_USER_REFRESH)}},
+		{"Events", "DELETE FROM user_event WHERE created < ?", []any{time.Now().Add(-EVENT_RETENTION)}},
+	} {
+		tag, err := Database.ExecContext(ctx, job.Query, job.Args...)
+		if err != nil {
+			LoggerCleanup.Log(ERROR, "Cannot cleanup %s: %s", job.Name, err)
+			continue
+		}
+		if c, err := tag.RowsAffected(); err == nil && c > 0 {
+			LoggerCleanup.Log(INFO, "Removed %d expired %s", c, job.Name)
+		}
+	}
+}
+
+// Remove per-session settings whose session no longer exists
+func cleanupSessionSettings() {
+	ctx, cancel := context.WithTimeout(context.Background(), TIMEOUT_CONTEXT)
+	defer cancel()
+
+	matches, err := filepath.Glob(path.Join(DATA_DIRECTORY, "settings", "*.@*.raw"))
+	if err != nil {
+		LoggerCleanup.Log(ERROR, "Cannot cleanup session settings: %s", err)
+		return
+	}
+	for _, match := range matches {
+		userText, sessionText, ok := strings.Cut(strings.TrimSuffix(path.Base(match), ".raw"), ".@")
+		if !ok {
+			continue
+		}
+		UserID, err := strconv.ParseInt(userText, 10, 64)
+		if err != nil {
+			continue
+		}
+		SessionID, err := strconv.ParseInt(sessionText, 10, 64)
+		if err != nil {
+			continue
+		}
+
+		var exists bool
+		if err := Database.QueryRowContext(ctx,
+			"SELECT EXISTS (SELECT 1 FROM user_session WHERE id = ? AND user_id = ?)",
+			SessionID, UserID,
+		).Scan(&exists); err != nil {
+			LoggerCleanup.Log(ERROR, "Cannot cleanup session settings: %s", err)
+			return
+		}
+		if exists {
+			continue
+		}
+		if err := SettingsDelete(UserID, "@"+sessionText); err != nil && err != ErrStorageFileNotFound {
+			LoggerStorage.Data(ERROR, "Failed to Delete Session Settings", map[string]any{
+				"user_id":    UserID,
+				"session_id": SessionID,
+				"error":      err.Error(),
+			})
+		}
+	}
+}
@@ -4,15 +4,39 @@ import (
 	"context"
 	"database/sql"
 	"dsoob/backend/include"
+	"errors"
 	"path"
 	"sync"
 	"time"
 
-	_ "github.com/mattn/go-sqlite3"
+	"github.com/mattn/go-sqlite3"
 )
 
 var Database *sql.DB
 
+// Columns added to existing tables after their creation, the schema only creates missing tables so older
+// databases are given these columns before it runs. Constraints which cannot be added to an existing table
+// (e.g. UNIQUE) are replaced by the given index. New columns must be appended here as well as to the schema
+var databaseColumns = []struct {
+	Table      string
+	Column     string
+	Definition string
+	Index      string
+}{
+	{"user", "permissions", "INT NOT NULL DEFAULT 0", ""},
+	{"user", "suspended_at", "TIMESTAMP", ""},
+	{"user", "suspended_reason", "TEXT", ""},
+	{"user", "deleted_at", "TIMESTAMP", ""},
+	{"user", "token_restore", "TEXT", "CREATE UNIQUE INDEX IF NOT EXISTS idx_user_token_restore ON user (token_restore)"},
+	{"user", "revision", "INTEGER NOT NULL DEFAULT 0", ""},
+	{"user", "mfa_removal_at", "TIMESTAMP", ""},
+	{"user", "token_mfa_removal", "TEXT", "CREATE UNIQUE INDEX IF NOT EXISTS idx_user_token_mfa_removal ON user (token_mfa_removal)"},
+	{"user_session", "elevated_method", "TEXT NOT NULL DEFAULT ''", ""},
+	{"user_session", "elevated_actions", "TEXT NOT NULL DEFAULT ''", ""},
+	{"user", "invited_by", "INTEGER", ""},
+	{"user", "email_normalized", "TEXT", "CREATE UNIQUE INDEX IF NOT EXISTS idx_user_email_normalized ON user (email_normalized)"},
+}
+
 func DatabaseSetup(stop context.Context, await *sync.WaitGroup) {
 	p := path.Join(DATA_DIRECTORY, "database", "main.db")
 	t := time.Now()
@@ -22,10 +46,18 @@ func DatabaseSetup(stop context.Context, await *sync.WaitGroup) {
 		LoggerDatabase.Log(FATAL, "Cannot open database: %s", err.Error())
 		return
 	}
+	if err := databaseUpgrade(db); err != nil {
+		LoggerDatabase.Log(FATAL, "Cannot upgrade database: %s", err.Error())
+		return
+	}
 	if _, err := db.Exec(include.DatabaseSchema); err != nil {
 		LoggerDatabase.Log(FATAL, "Cannot update database: %s", err.Error())
 		return
 	}
+	if err := databaseMigrate(db); err != nil {
+		LoggerDatabase.Log(FATAL, "Cannot migrate database: %s", err.Error())
+		return
+	}
 	db.SetMaxOpenConns(10)
 	db.SetMaxIdleConns(5)
 	Database = db
@@ -40,3 +72,154 @@ func DatabaseSetup(stop context.Context, await *sync.WaitGroup) {
 	}()
 	LoggerDatabase.Log(INFO, "Ready in %s", time.Since(t))
 }
+
+// Add missing Columns to existing Tables, runs before the schema so that its triggers and indexes can use them
+func databaseUpgrade(db *sql.DB) error {
+	ctx, cancel := context.WithTimeout(context.Background(), TIMEOUT_CONTEXT)
+	defer cancel()
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, c := range databaseColumns {
+		var TableExists, ColumnExists bool
+		if err := tx.QueryRowContext(ctx,
+			`SELECT
+				EXISTS (SELECT 1 FROM sqlite_master WHERE type = 'table' AND name = ?),
+				EXISTS (SELECT 1 FROM pragma_table_info(?) WHERE name = ?)`,
+			c.Table,
+			c.Table,
+			c.Column,
+		).Scan(
+			&TableExists,
+			&ColumnExists,
+		); err != nil {
+			return err
+		}
+
+		// New tables are created by the schema with every column
+		if !TableExists || ColumnExists {
+			continue
+		}
+		if _, err := tx.ExecContext(ctx, "ALTER TABLE "+c.Table+" ADD COLUMN "+c.Column+" "+c.Definition); err != nil {
+			return err
+		}
+		if c.Index != "" {
+			if _, err := tx.ExecContext(ctx, c.Index); err != nil {
+				return err
+			}
+		}
+		LoggerDatabase.Log(INFO, "Added column %s.%s", c.Table, c.Column)
+	}
+	return tx.Commit()
+}
+
+// Bring data created by older versions up to date, runs after the schema
+func databaseMigrate(db *sql.DB) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Fill Normalized Email Addresses
+	// 	Accounts whose normalized address is already taken keep NULL and must be resolved by hand
+	type pending struct {
+		ID           int64
+		EmailAddress string
+	}
+	rows, err := tx.QueryContext(ctx, "SELECT id, email_address FROM user WHERE email_normalized IS NULL")
+	if err != nil {
+		return err
+	}
+	users := []pending{}
+	for rows.Next() {
+		var u pending
+		if err := rows.Scan(&u.ID, &u.EmailAddress); err != nil {
+			rows.Close()
+			return err
+		}
+		users = append(users, u)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	filled := 0
+	for _, u := range users {
+		normalized, err := EmailNormalize(u.EmailAddress)
+		if err != nil {
+			LoggerDatabase.Data(WARN, "Cannot Normalize Email Address", map[string]any{
+				"user_id": u.ID,
+			})
+			continue
+		}
+		var ConflictID int64
+		err = tx.QueryRowContext(ctx,
+			"SELECT id FROM user WHERE email_normalized = ?",
+			normalized,
+		).Scan(
+			&ConflictID,
+		)
+		if err == nil {
+			LoggerDatabase.Data(WARN, "Duplicate Normalized Email Address", map[string]any{
+				"user_id":     u.ID,
+				"conflict_id": ConflictID,
+			})
+			continue
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx,
+			"UPDATE user SET email_normalized = ? WHERE id = ?",
+			normalized,
+			u.ID,
+		); err != nil {
+			return err
+		}
+		filled++
+	}
+
+	// Fill Profile Revisions
+	// 	Profiles created before revisions were tracked would otherwise never be seen by clients syncing changes
+	tag, err := tx.ExecContext(ctx,
+		"INSERT OR IGNORE INTO user_revision (user_id, deleted) SELECT id, deleted_at IS NOT NULL FROM user",
+	)
+	if err != nil {
+		return err
+	}
+	revised, err := tag.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx,
+		"UPDATE user SET revision = (SELECT revision FROM user_revision WHERE user_id = user.id) WHERE revision = 0",
+	); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	if filled > 0 {
+		LoggerDatabase.Log(INFO, "Filled %d normalized email address(es)", filled)
+	}
+	if revised > 0 {
+		LoggerDatabase.Log(INFO, "Filled %d profile revision(s)", revised)
+	}
+	return nil
+}
+
+// Reports whether the given error was caused by a UNIQUE constraint, e.g. when a concurrent request won the race
+func DatabaseUniqueViolation(err error) bool {
+	var e sqlite3.Error
+	return errors.As(err, &e) && e.ExtendedCode == sqlite3.ErrConstraintUnique
+}
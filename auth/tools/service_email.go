@@ -12,6 +12,10 @@ import (
 type LocalsEmailVerify struct {
 	Token string
 }
+type LocalsEmailVerifyChange struct {
+	Token    string
+	Lifetime string
+}
 type LocalsLoginForgotPassword struct {
 	Token string
 }
@@ -22,6 +26,14 @@ type LocalsLoginNewLocation struct {
 	DeviceBrowser  string
 	DeviceLocation string
 }
+type LocalsLoginEmailLink struct {
+	Token          string
+	Lifetime       string
+	Timestamp      string
+	IpAddress      string
+	DeviceBrowser  string
+	DeviceLocation string
+}
 type LocalsLoginNewDevice struct {
 	Timestamp      string
 	IpAddress      string
@@ -33,20 +45,50 @@ type LocalsLoginPasscode struct {
 	Lifetime string
 }
 type LocalsNotifyUserDeleted struct {
+	Content string
+	Reason  string
+}
+type LocalsNotifyUserDataExport struct {
+	Token    string
+	Lifetime string
+}
+type LocalsNotifyUserDeletionPending struct {
+	Token    string
+	Lifetime string
+}
+type LocalsNotifyUserMFARemoval struct {
+	Token    string
+	Lifetime string
+}
+type LocalsNotifyUserSuspended struct {
 	Reason string
 }
-type LocalsNotifyUserEmailModified struct{}
-type LocalsNotifyUserPasswordModified struct{}
+type LocalsNotifyUserEmailModified struct {
+	Email    string
+	Token    string
+	Lifetime string
+}
+type LocalsNotifyUserPasswordModified struct {
+	Reset    bool
+	Sessions int
+	Tokens   int64
+}
 
 var (
 	EmailVerify                     = setupEmailTemplate[LocalsEmailVerify]( /*---------------*/ "EMAIL_VERIFY", "Verify your Email Address")
+	EmailVerifyChange               = setupEmailTemplate[LocalsEmailVerifyChange]( /*---------*/ "EMAIL_VERIFY_CHANGE", "Confirm your New Email Address")
 	EmailLoginForgotPassword        = setupEmailTemplate[LocalsLoginForgotPassword]( /*-------*/ "LOGIN_FORGOT_PASSWORD", "Forgot Your Password?")
 	EmailLoginNewLocation           = setupEmailTemplate[LocalsLoginNewLocation]( /*----------*/ "LOGIN_NEW_LOCATION", "Allow Login from a New Location")
+	EmailLoginEmailLink             = setupEmailTemplate[LocalsLoginEmailLink]( /*------------*/ "LOGIN_EMAIL_LINK", "Your Login Link")
 	EmailLoginNewDevice             = setupEmailTemplate[LocalsLoginNewDevice]( /*------------*/ "LOGIN_NEW_DEVICE", "Login from a New Device")
 	EmailLoginPasscode              = setupEmailTemplate[LocalsLoginPasscode]( /*-------------*/ "LOGIN_PASSCODE", "Your One Time Passcode")
-	EmailNotifyUserDeleted          = setupEmailTemplate[LocalsNotifyUserDeleted]( /*---------*/ "NOTIFY_USER_DELETED", "Account Deleted")
-	EmailNotifyUserEmailModified    = setupEmailTemplate[LocalsNotifyUserEmailModified]( /*---*/ "NOTIFY_USER_EMAIL_MODIFIED", "Your Account Password has Changed")
-	EmailNotifyUserPasswordModified = setupEmailTemplate[LocalsNotifyUserPasswordModified]( /**/ "NOTIFY_USER_PASS_MODIFIED", "Your Account Email has Changed")
+	EmailNotifyUserDeleted          = setupEmailTemplate[LocalsNotifyUserDeleted]( /*---------*/ "NOTIFY_USER_DELETED", "Deletion Notice")
+	EmailNotifyUserDataExport       = setupEmailTemplate[LocalsNotifyUserDataExport]( /*------*/ "NOTIFY_USER_DATA_EXPORT", "Your Data Export is Ready")
+	EmailNotifyUserDeletionPending  = setupEmailTemplate[LocalsNotifyUserDeletionPending]( /**/ "NOTIFY_USER_DELETION_PENDING", "Account Scheduled for Deletion")
+	EmailNotifyUserMFARemoval       = setupEmailTemplate[LocalsNotifyUserMFARemoval]( /*------*/ "NOTIFY_USER_MFA_REMOVAL", "Two-Factor Authentication Removal Requested")
+	EmailNotifyUserSuspended        = setupEmailTemplate[LocalsNotifyUserSuspended]( /*-------*/ "NOTIFY_USER_SUSPENDED", "Account Suspended")
+	EmailNotifyUserEmailModified    = setupEmailTemplate[LocalsNotifyUserEmailModified]( /*---*/ "NOTIFY_USER_EMAIL_MODIFIED", "Your Account Email is Changing")
+	EmailNotifyUserPasswordModified = setupEmailTemplate[LocalsNotifyUserPasswordModified]( /**/ "NOTIFY_USER_PASS_MODIFIED", "Your Account Password has Changed")
 )
 
 func setupEmailTemplate[L any](filename, subjectLine string) func(toAddress string, locals L) {
@@ -25,6 +25,10 @@ var (
 	LoggerGeolocation = &LoggerInstance{source: "GEO"}
 	LoggerDatabase    = &LoggerInstance{source: "DATABASE"}
 	LoggerEmail       = &LoggerInstance{source: "EMAIL"}
+	LoggerToken       = &LoggerInstance{source: "TOKEN"}
+	LoggerAdmin       = &LoggerInstance{source: "ADMIN"}
+	LoggerCleanup     = &LoggerInstance{source: "CLEANUP"}
+	LoggerPassword    = &LoggerInstance{source: "PASSWORD"}
 )
 
 type LoggerInstance struct {
@@ -0,0 +1,186 @@
+package tools
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/bits"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Proof of Work challenges slow down automated signups and logins without relying on a third-party CAPTCHA,
+// challenges are signed rather than stored so the server can issue any number of them while solved challenges
+// are remembered until they expire so they cannot be replayed. The client must find a solution where
+// SHA-256(challenge + solution) starts with the given amount of zero bits, the difficulty grows with the
+// amount of challenges solved in the last minute and with repeated failures from the same address.
+// Only solved challenges count towards the load as issuing them is free and could be abused to raise it.
+
+type ProofClaims struct {
+	Difficulty int    `json:"dif"` // Required Leading Zero Bits
+	Nonce      string `json:"nnc"` // Random Nonce
+	ExpiresAt  int64  `json:"exp"` // Expires At UNIX Timestamp
+}
+
+var (
+	proofSecret   []byte
+	proofMutex    sync.Mutex
+	proofLoad     int
+	proofFailures = map[string]*ratelimitEntry{}
+	proofSolved   = map[string]int64{}
+)
+
+func ProofSetup(stop context.Context, await *sync.WaitGroup) {
+	t := time.Now()
+
+	if POW_DIFFICULTY <= 0 {
+		LoggerHTTP.Log(INFO, "Proof of Work is disabled")
+		return
+	}
+	proofSecret = make([]byte, 32)
+	if _, err := rand.Read(proofSecret); err != nil {
+		LoggerHTTP.Log(FATAL, "Cannot generate proof of work secret: %s", err)
+		return
+	}
+
+	// Cleanup Logic
+	await.Add(1)
+	go func() {
+		defer await.Done()
+		interval := time.NewTicker(time.Minute)
+		defer interval.Stop()
+		for {
+			select {
+			case <-stop.Done():
+				return
+			case <-interval.C:
+				now := time.Now().Unix()
+				proofMutex.Lock()
+				proofLoad = 0
+				for k, v := range proofFailures {
+					if now > v.ExpiresAt {
+						delete(proofFailures, k)
+					}
+				}
+				for k, v := range proofSolved {
+					if now > v {
+						delete(proofSolved, k)
+					}
+				}
+				proofMutex.Unlock()
+			}
+		}
+	}()
+	LoggerHTTP.Log(INFO, "Proof of Work ready in %s", time.Since(t))
+}
+
+// Generate a signed Proof of Work Challenge for the given Address
+func ProofIssue(address string) (string, ProofClaims) {
+	now := time.Now()
+
+	// Calculate Difficulty
+	// 	Every doubling of load past POW_LOAD_STEP and every failure past POW_ATTEMPT_FREE
+	// 	doubles the expected amount of work required to solve the challenge
+	proofMutex.Lock()
+	difficulty := POW_DIFFICULTY + bits.Len(uint(proofLoad/POW_LOAD_STEP))
+	if e, ok := proofFailures[address]; ok && now.Unix() <= e.ExpiresAt {
+		difficulty += max(int(e.Usage)-POW_ATTEMPT_FREE, 0)
+	}
+	proofMutex.Unlock()
+
+	claims := ProofClaims{
+		Difficulty: min(difficulty, POW_DIFFICULTY_LIMIT),
+		Nonce:      GenerateTokenString(),
+		ExpiresAt:  now.Add(POW_LIFETIME).Unix(),
+	}
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		panic(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(payload) + "." +
+		base64.RawURLEncoding.EncodeToString(proofSignature(payload, address)), claims
+}
+
+// Verify a Solution in the format "challenge:solution", each challenge is only accepted once
+func ProofVerify(address, value string) bool {
+
+	challenge, solution, ok := strings.Cut(value, ":")
+	if !ok || len(solution) > 64 {
+		return false
+	}
+	encodedPayload, encodedSignature, ok := strings.Cut(challenge, ".")
+	if !ok {
+		return false
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encodedPayload)
+	if err != nil {
+		return false
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(encodedSignature)
+	if err != nil {
+		return false
+	}
+
+	// Verify Signature
+	// 	Challenges are bound to the address they were issued to
+	var claims ProofClaims
+	if !hmac.Equal(signature, proofSignature(payload, address)) {
+		return false
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return false
+	}
+	if time.Now().Unix() >= claims.ExpiresAt {
+		return false
+	}
+
+	// Verify Solution
+	digest := sha256.Sum256([]byte(challenge + solution))
+	zeros := 0
+	for _, b := range digest {
+		zeros += bits.LeadingZeros8(b)
+		if b != 0 {
+			break
+		}
+	}
+	if zeros < claims.Difficulty {
+		return false
+	}
+
+	// Verify Replay
+	proofMutex.Lock()
+	defer proofMutex.Unlock()
+	if _, used := proofSolved[challenge]; used {
+		return false
+	}
+	proofSolved[challenge] = claims.ExpiresAt
+	proofLoad++
+	return true
+}
+
+// Count a failed Login or Password Reset from the given Address, raising the difficulty of its next challenges
+func ProofFail(address string) {
+	if POW_DIFFICULTY <= 0 {
+		return
+	}
+	now := time.Now().Unix()
+	proofMutex.Lock()
+	defer proofMutex.Unlock()
+	if e, ok := proofFailures[address]; ok && now <= e.ExpiresAt {
+		e.Usage++
+	} else {
+		proofFailures[address] = &ratelimitEntry{1, now + int64(POW_ATTEMPT_WINDOW.Seconds())}
+	}
+}
+
+// Sign the given Challenge Payload for the given Address
+func proofSignature(payload []byte, address string) []byte {
+	mac := hmac.New(sha256.New, proofSecret)
+	mac.Write(payload)
+	mac.Write([]byte(address))
+	return mac.Sum(nil)
+}
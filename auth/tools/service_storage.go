@@ -26,6 +26,43 @@ func StoragePublicCreate(filepath, filename string, perms os.FileMode) (io.Write
 	return os.OpenFile(fp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perms)
 }
 
+// Create a private file in the `{DATA_DIRECTORY}/private/{filepath}{filename}` directory
+func StoragePrivateCreate(filepath, filename string) (io.WriteCloser, error) {
+	fd := path.Join(DATA_DIRECTORY, "private", filepath)
+	fp := path.Join(fd, filename)
+
+	if err := os.MkdirAll(fd, FILEMODE_SECURE); err != nil {
+		return nil, err
+	}
+
+	return os.OpenFile(fp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, FILEMODE_SECURE)
+}
+
+// Read a private file in the `{DATA_DIRECTORY}/private/` directory
+func StoragePrivateRead(filename string) ([]byte, error) {
+	data, err := os.ReadFile(path.Join(DATA_DIRECTORY, "private", filename))
+	if os.IsNotExist(err) {
+		return nil, ErrStorageFileNotFound
+	}
+	return data, err
+}
+
+// Delete a private file in the `{DATA_DIRECTORY}/private/` directory,
+// files that do not exist are ignored as they may predate this storage
+func StoragePrivateDelete(filenames ...string) error {
+	var errors []string
+	for _, fn := range filenames {
+		fp := path.Join(DATA_DIRECTORY, "private", fn)
+		if err := os.RemoveAll(fp); err != nil {
+			errors = append(errors, err.Error())
+		}
+	}
+	if len(errors) > 0 {
+		return fmt.Errorf("deletion errors: %s", strings.Join(errors, ","))
+	}
+	return nil
+}
+
 // Delete a public file in the `{DATA_DIRECTORY}/public/` directory
 func StoragePublicDelete(filenames ...string) error {
 	var errors []string
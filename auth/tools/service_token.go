@@ -0,0 +1,343 @@
+package tools
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Access Tokens are signed with a rotating set of keys and can be verified without a database lookup,
+// the newest key is used for signing while older keys are kept around until every token they have
+// signed has expired. Refresh Tokens are stored as the session token and are rotated on every use.
+// Revoked sessions are stored until their outstanding access tokens have expired so that a restart
+// does not make them valid again, the list is reloaded alongside the keyring.
+
+type AccessClaims struct {
+	KeyID     int64 `json:"kid"` // Signing Key ID
+	SessionID int64 `json:"sid"` // Relevant Session ID
+	UserID    int64 `json:"uid"` // Relevant User ID
+	IssuedAt  int64 `json:"iat"` // Issued At UNIX Timestamp
+	ExpiresAt int64 `json:"exp"` // Expires At UNIX Timestamp
+}
+
+var (
+	keyringMutex    sync.RWMutex
+	keyringCurrent  int64
+	keyringSecrets  = map[int64][]byte{}
+	revokedMutex    sync.Mutex
+	revokedSessions = map[int64]time.Time{}
+)
+
+func TokenSetup(stop context.Context, await *sync.WaitGroup) {
+	t := time.Now()
+
+	if err := keyringRefresh(); err != nil {
+		LoggerToken.Log(FATAL, "Cannot prepare keyring: %s", err)
+		return
+	}
+
+	// Rotation Logic
+	await.Add(1)
+	go func() {
+		defer await.Done()
+		interval := time.NewTicker(time.Minute)
+		defer interval.Stop()
+		for {
+			select {
+			case <-stop.Done():
+				LoggerToken.Log(INFO, "Closed")
+				return
+			case <-interval.C:
+				if err := keyringRefresh(); err != nil {
+					LoggerToken.Log(ERROR, "Cannot refresh keyring: %s", err)
+				}
+			}
+		}
+	}()
+	LoggerToken.Log(INFO, "Ready in %s", time.Since(t))
+}
+
+// Prune expired keys, generate a new key if the current one is due for rotation and reload the keyring and revocations
+func keyringRefresh() error {
+	ctx, cancel := context.WithTimeout(context.Background(), TIMEOUT_CONTEXT)
+	defer cancel()
+
+	tx, err := Database.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Prune Expired Keys and Revocations
+	if _, err := tx.ExecContext(ctx,
+		"DELETE FROM token_key WHERE created < ?",
+		time.Now().Add(-TOKEN_KEY_ROTATION-TOKEN_LIFETIME_USER_ACCESS),
+	); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx,
+		"DELETE FROM token_revoked WHERE expires < ?",
+		time.Now(),
+	); err != nil {
+		return err
+	}
+
+	// Rotate Keys
+	var newest time.Time
+	err = tx.QueryRowContext(ctx,
+		"SELECT created FROM token_key ORDER BY created DESC LIMIT 1",
+	).Scan(
+		&newest,
+	)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+	rotate := errors.Is(err, sql.ErrNoRows) || time.Since(newest) > TOKEN_KEY_ROTATION
+	if rotate {
+		secret := make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx,
+			"INSERT INTO token_key (id, created, secret) VALUES (?, ?, ?)",
+			GenerateSnowflake(),
+			time.Now(),
+			secret,
+		); err != nil {
+			return err
+		}
+	}
+
+	// Reload Keyring
+	rows, err := tx.QueryContext(ctx, "SELECT id, secret FROM token_key ORDER BY created DESC")
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	var (
+		current int64
+		secrets = map[int64][]byte{}
+	)
+	for rows.Next() {
+		var id int64
+		var secret []byte
+		if err := rows.Scan(&id, &secret); err != nil {
+			return err
+		}
+		if current == 0 {
+			current = id
+		}
+		secrets[id] = secret
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	// Reload Revocations
+	rows, err = tx.QueryContext(ctx, "SELECT session_id, expires FROM token_revoked")
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	revoked := map[int64]time.Time{}
+	for rows.Next() {
+		var id int64
+		var expires time.Time
+		if err := rows.Scan(&id, &expires); err != nil {
+			return err
+		}
+		revoked[id] = expires
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	keyringMutex.Lock()
+	keyringCurrent = current
+	keyringSecrets = secrets
+	keyringMutex.Unlock()
+
+	// Sessions revoked since the query are kept until they expire
+	now := time.Now()
+	revokedMutex.Lock()
+	for id, expires := range revokedSessions {
+		if now.Before(expires) {
+			revoked[id] = expires
+		}
+	}
+	revokedSessions = revoked
+	revokedMutex.Unlock()
+
+	if rotate {
+		LoggerToken.Log(INFO, "Rotated signing key, %d key(s) in use", len(secrets))
+	}
+	return nil
+}
+
+// Generate a signed Access Token for the given Session
+func GenerateAccessToken(sessionID, userID int64) (string, time.Time) {
+	now := time.Now()
+	expires := now.Add(TOKEN_LIFETIME_USER_ACCESS)
+
+	keyringMutex.RLock()
+	keyID, secret := keyringCurrent, keyringSecrets[keyringCurrent]
+	keyringMutex.RUnlock()
+
+	claims := AccessClaims{
+		KeyID:     keyID,
+		SessionID: sessionID,
+		UserID:    userID,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: expires.Unix(),
+	}
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		panic(err)
+	}
+
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(payload)
+	return base64.RawURLEncoding.EncodeToString(payload) + "." +
+		base64.RawURLEncoding.EncodeToString(mac.Sum(nil)), expires
+}
+
+// Verify the signature and lifetime of an Access Token
+func ParseAccessToken(token string) (bool, AccessClaims) {
+	var claims AccessClaims
+
+	encodedPayload, encodedSignature, ok := strings.Cut(token, ".")
+	if !ok {
+		return false, claims
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encodedPayload)
+	if err != nil {
+		return false, claims
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(encodedSignature)
+	if err != nil {
+		return false, claims
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return false, claims
+	}
+
+	// Verify Signature
+	keyringMutex.RLock()
+	secret, ok := keyringSecrets[claims.KeyID]
+	keyringMutex.RUnlock()
+	if !ok {
+		return false, claims
+	}
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(payload)
+	if !hmac.Equal(signature, mac.Sum(nil)) {
+		return false, claims
+	}
+
+	// Verify Lifetime
+	if time.Now().Unix() >= claims.ExpiresAt {
+		return false, claims
+	}
+
+	// Verify Revocation
+	revokedMutex.Lock()
+	_, revoked := revokedSessions[claims.SessionID]
+	revokedMutex.Unlock()
+	if revoked {
+		return false, claims
+	}
+
+	return true, claims
+}
+
+// Reject any outstanding Access Tokens for the given Sessions,
+// this should be called whenever a session is deleted
+func RevokeAccessTokens(sessionIDs ...int64) {
+	if len(sessionIDs) == 0 {
+		return
+	}
+	expires := time.Now().Add(TOKEN_LIFETIME_USER_ACCESS)
+	revokedMutex.Lock()
+	for _, id := range sessionIDs {
+		revokedSessions[id] = expires
+	}
+	revokedMutex.Unlock()
+
+	// Persist Revocations
+	// 	Tokens are rejected by this instance immediately, the database copy survives restarts
+	ctx, cancel := context.WithTimeout(context.Background(), TIMEOUT_CONTEXT)
+	defer cancel()
+
+	for _, id := range sessionIDs {
+		if _, err := Database.ExecContext(ctx,
+			"INSERT OR REPLACE INTO token_revoked (session_id, expires) VALUES (?, ?)",
+			id,
+			expires,
+		); err != nil {
+			LoggerToken.Log(ERROR, "Cannot persist revoked session %d: %s", id, err)
+		}
+	}
+}
+
+// Delete every Session belonging to the given User except for exceptSessionID (use 0 to delete all),
+// the returned Session IDs should be passed to RevokeAccessTokens once the transaction is committed
+func DeleteUserSessions(ctx context.Context, tx *sql.Tx, userID, exceptSessionID int64) ([]int64, error) {
+	rows, err := tx.QueryContext(ctx,
+		"DELETE FROM user_session WHERE user_id = ? AND id != ? RETURNING id",
+		userID,
+		exceptSessionID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sessionIDs := []int64{}
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		sessionIDs = append(sessionIDs, id)
+	}
+	return sessionIDs, rows.Err()
+}
+
+// Delete every Personal Access Token belonging to the given User, these are checked against the
+// database on every request so no further revocation is needed once the transaction is committed
+func DeleteUserTokens(ctx context.Context, tx *sql.Tx, userID int64) (int64, error) {
+	tag, err := tx.ExecContext(ctx,
+		"DELETE FROM user_token WHERE user_id = ?",
+		userID,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected()
+}
+
+// Generate Response for a Login or Refresh, the refresh token must be the token stored for the session
+func SessionTokenResponse(userID, sessionID int64, refreshToken string) map[string]any {
+	accessToken, accessExpires := GenerateAccessToken(sessionID, userID)
+	return map[string]any{
+		"user_id":       userID,
+		"session_id":    sessionID,
+		"prefix":        TOKEN_PREFIX_USER,
+		"token":         accessToken,
+		"expires":       accessExpires.Unix(),
+		"refresh_token": refreshToken,
+	}
+}
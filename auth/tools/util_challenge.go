@@ -0,0 +1,181 @@
+package tools
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Challenges are single use secrets sent to the user to prove ownership of their email address,
+// either as a short passcode entered by the user or as a long token embedded into a link.
+// Only a hash of the secret is stored and every user has at most one challenge per purpose,
+// passcodes are removed once used, once expired or after CHALLENGE_ATTEMPT_LIMIT incorrect guesses.
+
+const (
+	CHALLENGE_ESCALATION     = "escalation"     // Passcode: Elevate Session
+	CHALLENGE_LOGIN_LOCATION = "login_location" // Token: Allow Login from a New Location (Data: IP Address)
+	CHALLENGE_LOGIN_EMAIL    = "login_email"    // Token: Login without a Password
+	CHALLENGE_PASSWORD_RESET = "password_reset" // Token: Reset Password
+	CHALLENGE_EMAIL_CHANGE   = "email_change"   // Token: Confirm New Email Address (Data: New Email Address)
+	CHALLENGE_EMAIL_REVERT   = "email_revert"   // Token: Undo Email Change (Data: Previous Email Address)
+)
+
+type Challenge struct {
+	ID       int64
+	UserID   int64
+	Data     string
+	Attempts int
+}
+
+var (
+	ErrChallengeNotFound  = errors.New("challenge not found")
+	ErrChallengeIncorrect = errors.New("challenge incorrect")
+)
+
+// Satisfied by both the Database and Transactions
+type challengeExecutor interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// Generate Hash for the given Challenge Secret, passcodes are case-insensitive
+func ChallengeHash(secret string) string {
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(strings.ToUpper(secret))))
+}
+
+// Create a new Challenge for the given User, replacing their previous challenge of the same purpose
+func ChallengeCreate(ctx context.Context, db challengeExecutor, userID int64, purpose, secret, data string, lifetime time.Duration) error {
+	if _, err := db.ExecContext(ctx,
+		"DELETE FROM user_challenge WHERE user_id = ? AND purpose = ?",
+		userID,
+		purpose,
+	); err != nil {
+		return err
+	}
+	_, err := db.ExecContext(ctx,
+		`INSERT INTO user_challenge (id, expires, user_id, purpose, secret_hash, data)
+		VALUES (?, ?, ?, ?, ?, ?)`,
+		GenerateSnowflake(),
+		time.Now().Add(lifetime),
+		userID,
+		purpose,
+		ChallengeHash(secret),
+		data,
+	)
+	return err
+}
+
+// Find the usable Challenge matching the given Secret, used for tokens where the user is not yet known
+func ChallengeLookup(ctx context.Context, purpose, secret string) (Challenge, error) {
+	var c Challenge
+	err := Database.QueryRowContext(ctx,
+		`SELECT id, user_id, data, attempts FROM user_challenge
+		WHERE purpose = ? AND secret_hash = ? AND expires > ? AND attempts < ?`,
+		purpose,
+		ChallengeHash(secret),
+		time.Now(),
+		CHALLENGE_ATTEMPT_LIMIT,
+	).Scan(
+		&c.ID,
+		&c.UserID,
+		&c.Data,
+		&c.Attempts,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return c, ErrChallengeNotFound
+	}
+	return c, err
+}
+
+// Verify a Secret against the current Challenge of the given User, consuming it if correct.
+// Returns ErrChallengeNotFound if there is no usable challenge or ErrChallengeIncorrect on mismatch
+func ChallengeVerify(ctx context.Context, userID int64, purpose, secret string) (Challenge, error) {
+	var (
+		c    Challenge
+		hash string
+	)
+
+	// Count Attempt
+	// 	The attempt is counted before comparing so that concurrent guesses cannot exceed the limit
+	err := Database.QueryRowContext(ctx,
+		`UPDATE user_challenge SET attempts = attempts + 1
+		WHERE user_id = ? AND purpose = ? AND expires > ? AND attempts < ?
+		RETURNING id, user_id, data, attempts, secret_hash`,
+		userID,
+		purpose,
+		time.Now(),
+		CHALLENGE_ATTEMPT_LIMIT,
+	).Scan(
+		&c.ID,
+		&c.UserID,
+		&c.Data,
+		&c.Attempts,
+		&hash,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return c, ErrChallengeNotFound
+	}
+	if err != nil {
+		return c, err
+	}
+	if !CompareStringConstant(hash, ChallengeHash(secret)) {
+		return c, ErrChallengeIncorrect
+	}
+
+	// Consume Challenge
+	// 	The attempt limit is ignored here as the correct guess may have been the last one allowed
+	tag, err := Database.ExecContext(ctx,
+		"DELETE FROM user_challenge WHERE id = ?",
+		c.ID,
+	)
+	if err != nil {
+		return c, err
+	}
+	if n, err := tag.RowsAffected(); err != nil {
+		return c, err
+	} else if n == 0 {
+		return c, ErrChallengeNotFound
+	}
+	return c, nil
+}
+
+// Count an incorrect attempt against the given Challenge, it becomes unusable once the limit is reached
+func ChallengeFail(ctx context.Context, challengeID int64) error {
+	_, err := Database.ExecContext(ctx,
+		"UPDATE user_challenge SET attempts = attempts + 1 WHERE id = ?",
+		challengeID,
+	)
+	return err
+}
+
+// Consume the given Challenge, returns ErrChallengeNotFound if it was already used by another request
+func ChallengeConsume(ctx context.Context, db challengeExecutor, challengeID int64) error {
+	tag, err := db.ExecContext(ctx,
+		"DELETE FROM user_challenge WHERE id = ? AND attempts < ?",
+		challengeID,
+		CHALLENGE_ATTEMPT_LIMIT,
+	)
+	if err != nil {
+		return err
+	}
+	if c, err := tag.RowsAffected(); err != nil {
+		return err
+	} else if c == 0 {
+		return ErrChallengeNotFound
+	}
+	return nil
+}
+
+// Remove the Challenge of the given purpose from the User, if any
+func ChallengeDelete(ctx context.Context, db challengeExecutor, userID int64, purpose string) error {
+	_, err := db.ExecContext(ctx,
+		"DELETE FROM user_challenge WHERE user_id = ? AND purpose = ?",
+		userID,
+		purpose,
+	)
+	return err
+}
@@ -22,26 +22,86 @@ const (
 	EPOCH_SECONDS                            = EPOCH_MILLI / 1000  // Generic EPOCH in Seconds
 	TIMEOUT_SHUTDOWN                         = 1 * time.Minute     // Default Timeout for Shutdowns
 	TIMEOUT_CONTEXT                          = 10 * time.Second    // Default Timeout for Requests
-	PASSWORD_HASH_EFFORT                     = 12                  // Password Hashing Effort
+	PASSWORD_LENGTH_LIMIT                    = 256                 // Maximum Password Length
+	PASSWORD_STRENGTH_LIMIT                  = 100                 // Characters considered when Estimating Password Strength
+	PASSWORD_SALT_LENGTH                     = 16                  // Argon2id Salt Length
+	PASSWORD_KEY_LENGTH                      = 32                  // Argon2id Hash Length
+	BREACHED_PASSWORDS_FILE                  = "breached.kani"     // Breached Password Filter, read from the Private Directory
 	PASSWORD_HISTORY_LIMIT                   = 5                   // Password History Length
 	PASSWORD_CONCURRENT_LIMIT                = 8                   // Password Hashing Concurrency Limit
 	MFA_PASSCODE_LENGTH                      = 6                   // TOTP Passcode String Length (Do Not Change)
 	MFA_RECOVERY_LENGTH                      = 8                   // TOTP Recovery Code Length (Do Not Change)
 	TOKEN_LIFETIME_USER_ELEVATION            = 10 * time.Minute    // Lifetime for User Elevation
+	TOKEN_LIFETIME_USER_HIGH_RISK            = 5 * time.Minute     // Lifetime for User Elevation permitting High-Risk Actions
 	TOKEN_LIFETIME_USER_COOKIE               = 30 * 24 * time.Hour // Lifetime for User Cookie
+	TOKEN_LIFETIME_USER_ACCESS               = 15 * time.Minute    // Lifetime for User Access Token
+	TOKEN_LIFETIME_USER_REFRESH              = 30 * 24 * time.Hour // Lifetime for Unused User Refresh Token
+	TOKEN_KEY_ROTATION                       = 24 * time.Hour      // Access Token Signing Key Rotation Interval
 	TOKEN_LIFETIME_EMAIL_PASSCODE            = 15 * time.Minute    // Lifetime for MFA Passcode
 	TOKEN_LIFETIME_EMAIL_LOGIN               = 24 * time.Hour      // Lifetime for Verify Login Token
+	TOKEN_LIFETIME_EMAIL_LINK                = 15 * time.Minute    // Lifetime for Passwordless Login Token
 	TOKEN_LIFETIME_EMAIL_VERIFY              = 24 * time.Hour      // Lifetime for Verify Email Token
 	TOKEN_LIFETIME_EMAIL_RESET               = 24 * time.Hour      // Lifetime for Password Reset Token
+	TOKEN_LIFETIME_EMAIL_CHANGE              = 24 * time.Hour      // Lifetime for Email Change Token
+	TOKEN_LIFETIME_EMAIL_REVERT              = 7 * 24 * time.Hour  // Lifetime for Email Change Revert Token
+	TOKEN_LIFETIME_PAIRING                   = 5 * time.Minute     // Lifetime for Device Pairing Code
+	CHALLENGE_ATTEMPT_LIMIT                  = 5                   // Incorrect Attempts before a Challenge is Discarded
+	POW_LIFETIME                             = 2 * time.Minute     // Lifetime for Proof of Work Challenges
+	POW_DIFFICULTY_LIMIT                     = 24                  // Maximum Leading Zero Bits required by Proof of Work
+	POW_LOAD_STEP                            = 50                  // Solved Challenges per Minute before Proof of Work Difficulty Increases
+	POW_ATTEMPT_FREE                         = 3                   // Failed Attempts per Address before Proof of Work Difficulty Increases
+	POW_ATTEMPT_WINDOW                       = 15 * time.Minute    // Period in which Failed Attempts per Address are Counted
+	POW_SIGNUP_LIMIT                         = 20                  // Signups per Address per Day while Proof of Work is Enabled
+	POW_HEADER                               = "X-Proof-Of-Work"   // Header containing the Proof of Work Solution
+	PAIRING_CODE_LENGTH                      = 8                   // Device Pairing Code Length
+	PAIRING_POLL_TIMEOUT                     = 5 * time.Second     // Device Pairing Long-Poll Duration
+	PAIRING_POLL_INTERVAL                    = 1 * time.Second     // Device Pairing Long-Poll Interval
+	APPLICATION_LIMIT                        = 10                  // Maximum Applications per User
+	PERSONAL_TOKEN_LIMIT                     = 25                  // Maximum Personal Access Tokens per User
+	INVITE_USES_LIMIT                        = 5                   // Maximum Uses per Invite created by a User
+	INVITE_CODE_LENGTH                       = 12                  // Invite Code Length
+	INVITE_LIFETIME                          = 7 * 24 * time.Hour  // Default Lifetime for Invites created by a User
+	REGISTRATION_OPEN                        = "open"              // Registration Mode: Anyone can Signup
+	REGISTRATION_INVITE                      = "invite"            // Registration Mode: Signup requires an Invite Code
+	REGISTRATION_CLOSED                      = "closed"            // Registration Mode: Signup is Disabled
+	CLEANUP_INTERVAL                         = 1 * time.Hour       // Interval between Cleanup Jobs
+	EVENT_RETENTION                          = 90 * 24 * time.Hour // Security Events are Removed after this Period
+	TOKEN_LIFETIME_EXPORT                    = 24 * time.Hour      // Lifetime for Data Export Download
+	SETTINGS_HISTORY_LIMIT                   = 10                  // Previous Settings Versions kept per Namespace
+	SETTINGS_NAMESPACE_LIMIT                 = 16                  // Maximum Named Settings Namespaces per User
+	USERNAME_CHANGE_COOLDOWN                 = 30 * 24 * time.Hour // Minimum Time between Username Changes
+	USERNAME_RESERVATION                     = 90 * 24 * time.Hour // Previous Usernames are Reserved for their Owner
+	SCOPE_ACCOUNT                            = "account"           // Account Management (User Sessions Only)
+	SCOPE_PROFILE_READ                       = "profile:read"      // Read Profile
+	SCOPE_PROFILE_WRITE                      = "profile:write"     // Edit Profile, Avatar and Banner
+	SCOPE_SETTINGS_READ                      = "settings:read"     // Read Settings
+	SCOPE_SETTINGS_WRITE                     = "settings:write"    // Write Settings
 	TOKEN_BYTE_LENGTH                        = 64
 	TOKEN_PREFIX_USER                        = "User "
+	TOKEN_PREFIX_BOT                         = "Bot "
+	TOKEN_PREFIX_PERSONAL                    = "Token "
 	SESSION_KEY                   contextKey = "gloopert"
 )
 
+// Administrative Permissions are stored as a bitfield on the user,
+// roles are simply preset combinations of these permissions
+const (
+	PERMISSION_USERS_READ     int64 = 1 << 0 // Search Users and View Sessions
+	PERMISSION_USERS_SUSPEND  int64 = 1 << 1 // Suspend and Unsuspend Users
+	PERMISSION_USERS_SECURITY int64 = 1 << 2 // Force Password Resets and Remove MFA
+	PERMISSION_USERS_CONTENT  int64 = 1 << 3 // Delete User Avatars and Banners
+	PERMISSION_USERS_INVITE   int64 = 1 << 4 // Create Invites beyond User Limits
+	ROLE_MODERATOR                  = PERMISSION_USERS_READ | PERMISSION_USERS_SUSPEND | PERMISSION_USERS_CONTENT
+	ROLE_ADMINISTRATOR              = ROLE_MODERATOR | PERMISSION_USERS_SECURITY | PERMISSION_USERS_INVITE
+)
+
 var (
+	SCOPES_GRANTABLE   = []string{SCOPE_PROFILE_READ, SCOPE_PROFILE_WRITE, SCOPE_SETTINGS_READ, SCOPE_SETTINGS_WRITE}
 	DATA_DIRECTORY     = envString("DATA_DIRECTORY", "./data")
 	EMAIL_SMTP_HOST    = envString("EMAIL_SMTP_HOST", "127.0.0.1:1273")
 	EMAIL_SMTP_ADDRESS = envString("EMAIL_SMTP_ADDRESS", "noreply@example.org")
+	EMAIL_DOT_DOMAINS  = envSlice("EMAIL_DOT_DOMAINS", ",", []string{"gmail.com"})
+	EMAIL_TAG_DOMAINS  = envSlice("EMAIL_TAG_DOMAINS", ",", []string{"gmail.com", "outlook.com", "hotmail.com", "live.com", "icloud.com", "me.com", "mac.com", "proton.me", "protonmail.com", "pm.me", "fastmail.com"})
 	HTTP_ADDRESS       = envString("HTTP_ADDRESS", "127.0.0.1:8080")
 	HTTP_IP_HEADERS    = envSlice("HTTP_IP_HEADERS", ",", []string{"X-Forwarded-For"})
 	HTTP_IP_PROXIES    = envSlice("HTTP_IP_PROXIES", ",", []string{"127.0.0.1/8"})
@@ -49,6 +109,15 @@ var (
 	HTTP_TLS_CERT      = envString("HTTP_TLS_CERT", "tls_crt.pem")
 	HTTP_TLS_KEY       = envString("HTTP_TLS_KEY", "tls_key.pem")
 	HTTP_TLS_CA        = envString("HTTP_TLS_CA", "tls_ca.pem")
+	DELETE_GRACE_DAYS  = envNumber("DELETE_GRACE_DAYS", 14)
+	INVITE_LIMIT       = envNumber("INVITE_LIMIT", 10)
+	MFA_REMOVAL_DAYS   = envNumber("MFA_REMOVAL_DAYS", 7)
+	PASSWORD_MEMORY    = envNumber("PASSWORD_MEMORY", 64*1024)
+	PASSWORD_TIME      = envNumber("PASSWORD_TIME", 3)
+	PASSWORD_THREADS   = envNumber("PASSWORD_THREADS", 2)
+	PASSWORD_MIN_SCORE = envNumber("PASSWORD_MIN_SCORE", 3)
+	POW_DIFFICULTY     = envNumber("POW_DIFFICULTY", 0)
+	REGISTRATION_MODE  = envString("REGISTRATION_MODE", REGISTRATION_OPEN)
 )
 
 func init() {
@@ -60,6 +129,7 @@ func init() {
 		{FILEMODE_PUBLIC, "public"},
 		{FILEMODE_SECURE, "settings"},
 		{FILEMODE_SECURE, "database"},
+		{FILEMODE_SECURE, "private"},
 	} {
 		// Attempt to Create Directory
 		pth := path.Join(DATA_DIRECTORY, item.Directory)
@@ -0,0 +1,133 @@
+package tools
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Elevation is granted to a single session once the user proves their identity again, it is stored alongside
+// the session rather than in the access token so that it can be consumed. Every elevation records the method
+// that was used and the actions it permits, high-risk actions must be requested explicitly, are granted for a
+// shorter time and are consumed once performed so that each of them requires a fresh verification.
+// High-risk actions additionally require that the recorded method is the strongest one the account has.
+
+const (
+	ELEVATION_ACCOUNT_DELETE = "account_delete" // High-Risk: Delete Account
+	ELEVATION_EMAIL_CHANGE   = "email_change"   // High-Risk: Change Email Address
+	ELEVATION_MFA            = "mfa"            // Setup or Remove MFA and View Recovery Codes
+	ELEVATION_SESSIONS       = "sessions"       // Revoke Sessions and Pair Devices
+	ELEVATION_APPLICATIONS   = "applications"   // Create Applications and Reset their Secrets
+	ELEVATION_TOKENS         = "tokens"         // Create Personal Access Tokens
+	ELEVATION_EXPORT         = "export"         // Export Account Data
+	ELEVATION_USERNAME       = "username"       // Change Username
+)
+
+const (
+	ELEVATION_METHOD_EMAIL    = "email"    // Passcode sent to the Email Address
+	ELEVATION_METHOD_PASSWORD = "password" // Account Password
+)
+
+var (
+	ELEVATION_ACTIONS   = []string{ELEVATION_ACCOUNT_DELETE, ELEVATION_EMAIL_CHANGE, ELEVATION_MFA, ELEVATION_SESSIONS, ELEVATION_APPLICATIONS, ELEVATION_TOKENS, ELEVATION_EXPORT, ELEVATION_USERNAME}
+	ELEVATION_HIGH_RISK = []string{ELEVATION_ACCOUNT_DELETE, ELEVATION_EMAIL_CHANGE}
+	ELEVATION_DEFAULT   = []string{ELEVATION_MFA, ELEVATION_SESSIONS, ELEVATION_APPLICATIONS, ELEVATION_TOKENS, ELEVATION_EXPORT, ELEVATION_USERNAME}
+)
+
+var ErrElevationConsumed = errors.New("elevation consumed")
+
+// Restrict Request to Sessions elevated for the given Action, sending an error otherwise.
+// Only user sessions can be elevated, bots and personal access tokens are always refused
+func UseElevation(w http.ResponseWriter, r *http.Request, action string) bool {
+	session := GetSession(r)
+	if session.SessionID == 0 {
+		SendClientError(w, r, ERROR_MFA_ESCALATION_REQUIRED)
+		return false
+	}
+
+	var (
+		SessionElevatedUntil      time.Time
+		SessionElevatedMethod     string
+		SessionElevatedActionsRAW string
+		UserEmailVerified         bool
+		UserMFAEnabled            bool
+	)
+	err := Database.QueryRowContext(r.Context(),
+		`SELECT s.elevated_until, s.elevated_method, s.elevated_actions, u.email_verified, u.mfa_enabled
+		FROM user_session s JOIN user u ON u.id = s.user_id
+		WHERE s.id = ?`,
+		session.SessionID,
+	).Scan(
+		&SessionElevatedUntil,
+		&SessionElevatedMethod,
+		&SessionElevatedActionsRAW,
+		&UserEmailVerified,
+		&UserMFAEnabled,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		SendClientError(w, r, ERROR_GENERIC_UNAUTHORIZED)
+		return false
+	}
+	if err != nil {
+		SendServerError(w, r, err)
+		return false
+	}
+	if time.Now().After(SessionElevatedUntil) {
+		SendClientError(w, r, ERROR_MFA_ESCALATION_REQUIRED)
+		return false
+	}
+	if !slices.Contains(strings.Split(SessionElevatedActionsRAW, ARRAY_DELIMITER), action) {
+		SendClientError(w, r, ERROR_MFA_ESCALATION_INSUFFICIENT)
+		return false
+	}
+
+	// Verify Method
+	// 	The account may have gained a stronger factor since the session was elevated
+	if slices.Contains(ELEVATION_HIGH_RISK, action) &&
+		!slices.Contains(ElevationMethods(UserMFAEnabled, UserEmailVerified), SessionElevatedMethod) {
+		SendClientError(w, r, ERROR_MFA_ESCALATION_INSUFFICIENT)
+		return false
+	}
+	return true
+}
+
+// Return the strongest Methods available to an Account, any of them may be used for high-risk actions
+func ElevationMethods(mfaEnabled, emailVerified bool) []string {
+	switch {
+	case mfaEnabled:
+		return []string{MFA_METHOD_PASSCODE, MFA_METHOD_RECOVERY}
+	case emailVerified:
+		return []string{ELEVATION_METHOD_EMAIL}
+	default:
+		return []string{ELEVATION_METHOD_PASSWORD}
+	}
+}
+
+// Consume the Elevation of the given Session after performing a high-risk action,
+// returns ErrElevationConsumed if it has expired or was used by another request
+func ElevationConsume(ctx context.Context, tx *sql.Tx, sessionID int64) error {
+	now := time.Now()
+	tag, err := tx.ExecContext(ctx,
+		`UPDATE user_session SET
+			elevated_until   = ?,
+			elevated_method  = '',
+			elevated_actions = ''
+		WHERE id = ? AND elevated_until > ?`,
+		now,
+		sessionID,
+		now,
+	)
+	if err != nil {
+		return err
+	}
+	if c, err := tag.RowsAffected(); err != nil {
+		return err
+	} else if c == 0 {
+		return ErrElevationConsumed
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package tools
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+
+	"dsoob/backend/include"
+
+	"golang.org/x/net/idna"
+)
+
+// Email Addresses are normalized before checking for duplicates so that variations which deliver
+// to the same mailbox cannot be used to create multiple accounts. Domains are converted to their
+// ASCII form (IDNA), providers listed in EMAIL_TAG_DOMAINS ignore everything after a '+' and providers
+// listed in EMAIL_DOT_DOMAINS ignore dots. The address itself is stored as entered for delivery.
+
+var (
+	ErrEmailInvalid = errors.New("email address invalid")
+
+	// Domains delivering to the same Mailboxes as another Domain
+	EMAIL_DOMAIN_ALIASES = map[string]string{
+		"googlemail.com": "gmail.com",
+	}
+	emailDisposable = map[string]struct{}{}
+)
+
+func init() {
+	for _, domain := range strings.Fields(include.DisposableDomains) {
+		emailDisposable[domain] = struct{}{}
+	}
+}
+
+// Normalize the given Email Address for Duplicate Checks, returns ErrEmailInvalid if the domain cannot be converted
+func EmailNormalize(givenAddress string) (string, error) {
+	i := strings.LastIndexByte(givenAddress, '@')
+	if i < 1 {
+		return "", ErrEmailInvalid
+	}
+	domain, err := EmailDomainASCII(givenAddress[i+1:])
+	if err != nil {
+		return "", err
+	}
+	if alias, ok := EMAIL_DOMAIN_ALIASES[domain]; ok {
+		domain = alias
+	}
+
+	local := strings.ToLower(givenAddress[:i])
+	if slices.Contains(EMAIL_TAG_DOMAINS, domain) {
+		local, _, _ = strings.Cut(local, "+")
+	}
+	if slices.Contains(EMAIL_DOT_DOMAINS, domain) {
+		local = strings.ReplaceAll(local, ".", "")
+	}
+	if local == "" {
+		return "", ErrEmailInvalid
+	}
+	return local + "@" + domain, nil
+}
+
+// Convert the given Domain into its lowercase ASCII form using UTS #46 mapping,
+// compatibility forms are folded and existing 'xn--' labels are validated
+func EmailDomainASCII(givenDomain string) (string, error) {
+	domain, err := idna.Lookup.ToASCII(strings.TrimSuffix(givenDomain, "."))
+	if err != nil || !strings.Contains(domain, ".") {
+		return "", ErrEmailInvalid
+	}
+	return domain, nil
+}
+
+// Check whether the given ASCII Domain or any of its parent domains belong to a disposable email provider
+func EmailDisposable(givenDomain string) bool {
+	for domain := givenDomain; domain != ""; {
+		if _, ok := emailDisposable[domain]; ok {
+			return true
+		}
+		_, domain, _ = strings.Cut(domain, ".")
+	}
+	return false
+}
+
+// Normalize the given Email Address and ensure it can be used for an account, otherwise an error is sent to the client
+func ValidateEmailAddress(w http.ResponseWriter, r *http.Request, givenAddress string) (string, bool) {
+	normalized, err := EmailNormalize(givenAddress)
+	if err != nil {
+		SendClientError(w, r, ERROR_SIGNUP_EMAIL_INVALID)
+		return "", false
+	}
+	if EmailDisposable(normalized[strings.LastIndexByte(normalized, '@')+1:]) {
+		SendClientError(w, r, ERROR_SIGNUP_EMAIL_DISPOSABLE)
+		return "", false
+	}
+	return normalized, true
+}
@@ -0,0 +1,48 @@
+package tools
+
+import (
+	"net/http"
+)
+
+// Security Events are shown to the user so they can review activity on their account,
+// the detail field is optional and describes how or to what the event happened
+const (
+	EVENT_LOGIN                    = "login"                    // Detail: Login Method
+	EVENT_LOGIN_FAILED             = "login_failed"             // Detail: Reason
+	EVENT_LOGIN_LOCATION_APPROVED  = "login_location_approved"  // Detail: Approved IP Address
+	EVENT_LOGOUT                   = "logout"                   // Detail: Session ID
+	EVENT_SESSION_REVOKED          = "session_revoked"          // Detail: Session ID
+	EVENT_SESSION_REUSE            = "session_reuse"            // Detail: Session ID
+	EVENT_ESCALATION               = "escalation"               // Detail: Verification Method
+	EVENT_PASSWORD_CHANGED         = "password_changed"         // Detail: None
+	EVENT_PASSWORD_RESET           = "password_reset"           // Detail: Initiator
+	EVENT_USERNAME_CHANGED         = "username_changed"         // Detail: Previous Username
+	EVENT_EMAIL_CHANGED            = "email_changed"            // Detail: Previous Email Address
+	EVENT_EMAIL_REVERTED           = "email_reverted"           // Detail: Restored Email Address
+	EVENT_MFA_ENABLED              = "mfa_enabled"              // Detail: None
+	EVENT_MFA_DISABLED             = "mfa_disabled"             // Detail: Initiator
+	EVENT_MFA_RECOVERY_USED        = "mfa_recovery_used"        // Detail: None
+	EVENT_MFA_RECOVERY_REGENERATED = "mfa_recovery_regenerated" // Detail: None
+	EVENT_MFA_REMOVAL_REQUESTED    = "mfa_removal_requested"    // Detail: None
+	EVENT_MFA_REMOVAL_CANCELLED    = "mfa_removal_cancelled"    // Detail: None
+)
+
+// Append a Security Event for the given User, errors are only logged as the
+// action being recorded has already happened and should not be reported as failed
+func RecordUserEvent(r *http.Request, userID int64, event, detail string) {
+	if _, err := Database.ExecContext(r.Context(),
+		"INSERT INTO user_event (id, user_id, type, detail, ip_address, user_agent) VALUES (?, ?, ?, ?, ?, ?)",
+		GenerateSnowflake(),
+		userID,
+		event,
+		detail,
+		GetRemoteIP(r),
+		r.UserAgent(),
+	); err != nil {
+		LoggerDatabase.Data(ERROR, "Cannot Record User Event", map[string]any{
+			"user_id": userID,
+			"event":   event,
+			"error":   err.Error(),
+		})
+	}
+}
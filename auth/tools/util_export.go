@@ -0,0 +1,219 @@
+package tools
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path"
+	"strconv"
+	"time"
+)
+
+var exportImageExtensions = map[string]string{
+	"image/jpeg": "jpeg",
+	"image/png":  "png",
+	"image/gif":  "gif",
+	"image/webp": "webp",
+}
+
+// Return Path for a Data Export Archive, relative to the private directory
+func ExportPath(userID, exportID int64) string {
+	return path.Join("exports", strconv.FormatInt(userID, 10), strconv.FormatInt(exportID, 10)+".zip")
+}
+
+// Build a Data Export Archive for the given User and email them a download link once complete,
+// the export is removed if anything goes wrong so that the user may request another one
+func ExportUserData(userID, exportID int64, token string) {
+	t := time.Now()
+	if err := exportUserData(userID, exportID, token); err != nil {
+		LoggerStorage.Data(ERROR, "Data Export Failed", map[string]any{
+			"user_id":   userID,
+			"export_id": exportID,
+			"error":     err.Error(),
+		})
+		ctx, cancel := context.WithTimeout(context.Background(), TIMEOUT_CONTEXT)
+		defer cancel()
+		Database.ExecContext(ctx, "DELETE FROM user_export WHERE id = ?", exportID)
+		StoragePrivateDelete(ExportPath(userID, exportID))
+		return
+	}
+	LoggerStorage.Log(INFO, "Exported data for user %d in %s", userID, time.Since(t))
+}
+
+func exportUserData(userID, exportID int64, token string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), TIMEOUT_CONTEXT)
+	defer cancel()
+
+	// Collect Records
+	// 	Secrets such as password hashes, MFA secrets and tokens are never included
+	var records = map[string][]map[string]any{}
+	for _, item := range []struct {
+		Filename string
+		Query    string
+	}{
+		{"user.json", `SELECT
+			id, created, updated, email_address, email_verified, ip_address, mfa_enabled,
+			username, displayname, subtitle, biography, avatar_hash, banner_hash,
+			accent_banner, accent_border, accent_background,
+			permissions, suspended_at, suspended_reason, deleted_at
+		FROM user WHERE id = ?`},
+		{"sessions.json", `SELECT
+			id, created, updated, device_ip_address, device_user_agent, device_public_key
+		FROM user_session WHERE user_id = ?`},
+		{"applications.json", "SELECT id, created, updated, name, scopes FROM application WHERE owner_id = ?"},
+		{"tokens.json", "SELECT id, created, expires, name, scopes FROM user_token WHERE user_id = ?"},
+		{"events.json", "SELECT id, created, type, detail, ip_address, user_agent FROM user_event WHERE user_id = ?"},
+		{"usernames.json", "SELECT id, created, reserved_until, username FROM user_username WHERE user_id = ?"},
+	} {
+		rows, err := exportQuery(ctx, item.Query, userID)
+		if err != nil {
+			return err
+		}
+		records[item.Filename] = rows
+	}
+	if len(records["user.json"]) == 0 {
+		return fmt.Errorf("unknown user %d", userID)
+	}
+	user := records["user.json"][0]
+	for _, session := range records["sessions.json"] {
+		session["location"] = LookupLocation(fmt.Sprint(session["device_ip_address"]))
+		session["browser"] = LookupBrowser(fmt.Sprint(session["device_user_agent"]))
+	}
+
+	// Create Archive
+	exportPath := ExportPath(userID, exportID)
+	f, err := StoragePrivateCreate(path.Dir(exportPath), path.Base(exportPath))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	archive := zip.NewWriter(f)
+
+	for _, item := range []struct {
+		Filename string
+		Content  any
+	}{
+		{"user.json", user},
+		{"sessions.json", records["sessions.json"]},
+		{"applications.json", records["applications.json"]},
+		{"tokens.json", records["tokens.json"]},
+		{"events.json", records["events.json"]},
+	} {
+		w, err := archive.Create(item.Filename)
+		if err != nil {
+			return err
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(item.Content); err != nil {
+			return err
+		}
+	}
+
+	// Include Settings
+	namespaces, err := SettingsList(userID)
+	if err != nil {
+		return err
+	}
+	for _, namespace := range namespaces {
+		settings, err := SettingsRead(userID, namespace.Name)
+		if err == ErrStorageFileNotFound {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		filename := "settings.raw"
+		if namespace.Name != "" {
+			filename = path.Join("settings", namespace.Name+".raw")
+		}
+		w, err := archive.Create(filename)
+		if err != nil {
+			return err
+		}
+		if _, err := w.Write(settings); err != nil {
+			return err
+		}
+	}
+
+	// Include Images
+	// 	Images uploaded before originals were kept fall back to their largest format
+	for _, item := range []struct {
+		Name    string
+		Column  string
+		Options ImageOptions
+	}{
+		{"avatar", "avatar_hash", ImageOptionsAvatars},
+		{"banner", "banner_hash", ImageOptionsBanners},
+	} {
+		hash, ok := user[item.Column].(string)
+		if !ok {
+			continue
+		}
+		data, err := StoragePrivateRead(ImageOriginalPath(item.Options, userID, hash))
+		if err == ErrStorageFileNotFound {
+			data, err = os.ReadFile(path.Join(DATA_DIRECTORY, "public", ImagePaths(item.Options, userID, hash)[0]))
+		}
+		if err != nil {
+			return err
+		}
+		extension, ok := exportImageExtensions[http.DetectContentType(data)]
+		if !ok {
+			extension = "bin"
+		}
+		w, err := archive.Create(item.Name + "." + extension)
+		if err != nil {
+			return err
+		}
+		if _, err := w.Write(data); err != nil {
+			return err
+		}
+	}
+
+	if err := archive.Close(); err != nil {
+		return err
+	}
+
+	// Notify User
+	go EmailNotifyUserDataExport(fmt.Sprint(user["email_address"]),
+		LocalsNotifyUserDataExport{
+			Token:    token,
+			Lifetime: fmt.Sprint(TOKEN_LIFETIME_EXPORT.Hours()),
+		},
+	)
+	return nil
+}
+
+// Collect every row from the given query using their column names as keys
+func exportQuery(ctx context.Context, query string, args ...any) ([]map[string]any, error) {
+	rows, err := Database.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	results := []map[string]any{}
+	for rows.Next() {
+		values := make([]any, len(columns))
+		pointers := make([]any, len(columns))
+		for i := range values {
+			pointers[i] = &values[i]
+		}
+		if err := rows.Scan(pointers...); err != nil {
+			return nil, err
+		}
+		row := make(map[string]any, len(columns))
+		for i, column := range columns {
+			row[column] = values[i]
+		}
+		results = append(results, row)
+	}
+	return results, rows.Err()
+}
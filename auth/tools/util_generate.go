@@ -10,8 +10,10 @@ import (
 	"fmt"
 	"io"
 	"math/big"
+	"strings"
 	"time"
 
+	"golang.org/x/crypto/argon2"
 	"golang.org/x/crypto/bcrypt"
 )
 
@@ -28,6 +30,30 @@ func GeneratePasscode() string {
 	return fmt.Sprintf("%06d", n)
 }
 
+// Picks Random Characters for Device Pairing Codes
+func GeneratePairingCode() string {
+	return generateReadableCode(PAIRING_CODE_LENGTH)
+}
+
+// Picks Random Characters for Invite Codes
+func GenerateInviteCode() string {
+	return generateReadableCode(INVITE_CODE_LENGTH)
+}
+
+// Picks Random Characters for codes typed by users, ambiguous characters (0/O, 1/I) are excluded
+func generateReadableCode(length int) string {
+	const alphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+	code := make([]byte, length)
+	for i := range code {
+		n, err := rand.Int(rand.Reader, big.NewInt(int64(len(alphabet))))
+		if err != nil {
+			panic(err)
+		}
+		code[i] = alphabet[n.Int64()]
+	}
+	return string(code)
+}
+
 // Generate Six 8-Character Recovery Codes for MFA Setup
 func GenerateRecoveryCodes() []string {
 	codes := make([]string, 6)
@@ -64,37 +90,105 @@ func CompareTokenString(givenString string) bool {
 	return len(givenBytes) == TOKEN_BYTE_LENGTH
 }
 
-// Wrapper to Hash Password with Predefined Effort
+// Hash Password using Argon2id with the configured parameters, the result is encoded
+// in the PHC string format: $argon2id$v=19$m=<memory KiB>,t=<time>,p=<threads>$<salt>$<hash>
 func GeneratePasswordHash(givenPassword string) (string, error) {
 	hashSemaphore <- struct{}{}
 	defer func() { <-hashSemaphore }()
 
-	hashBytes, err := bcrypt.GenerateFromPassword(
-		[]byte(givenPassword),
-		PASSWORD_HASH_EFFORT,
-	)
-	if err != nil {
+	salt := make([]byte, PASSWORD_SALT_LENGTH)
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
 		return "", err
 	}
-	return string(hashBytes), nil
+	hash := argon2.IDKey(
+		[]byte(givenPassword),
+		salt,
+		uint32(PASSWORD_TIME),
+		uint32(PASSWORD_MEMORY),
+		uint8(PASSWORD_THREADS),
+		PASSWORD_KEY_LENGTH,
+	)
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version,
+		PASSWORD_MEMORY,
+		PASSWORD_TIME,
+		PASSWORD_THREADS,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(hash),
+	), nil
 }
 
-// Wrapper to Compare Password Against Given String
+// Compare Password against the given Argon2id or legacy bcrypt Hash
 func ComparePasswordHash(givenHash, givenPassword string) (bool, error) {
 	hashSemaphore <- struct{}{}
 	defer func() { <-hashSemaphore }()
 
-	err := bcrypt.CompareHashAndPassword(
-		[]byte(givenHash),
-		[]byte(givenPassword),
+	// Legacy Hash
+	if !strings.HasPrefix(givenHash, "$argon2id$") {
+		// bcrypt ignores anything past 72 bytes, so longer
+		// passwords could never have been the original one
+		if len(givenPassword) > 72 {
+			return false, nil
+		}
+		err := bcrypt.CompareHashAndPassword(
+			[]byte(givenHash),
+			[]byte(givenPassword),
+		)
+		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		return true, nil
+	}
+
+	// Argon2id Hash
+	var (
+		version            int
+		memory, iterations uint32
+		threads            uint8
 	)
-	if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
-		return false, nil
+	parts := strings.Split(givenHash, "$")
+	if len(parts) != 6 {
+		return false, errors.New("malformed argon2id hash")
+	}
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil {
+		return false, err
+	}
+	if version != argon2.Version {
+		return false, errors.New("unsupported argon2id version")
+	}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &memory, &iterations, &threads); err != nil {
+		return false, err
 	}
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
 	if err != nil {
 		return false, err
 	}
-	return true, nil
+	hash, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil {
+		return false, err
+	}
+	otherHash := argon2.IDKey(
+		[]byte(givenPassword),
+		salt,
+		iterations,
+		memory,
+		threads,
+		uint32(len(hash)),
+	)
+	return subtle.ConstantTimeCompare(hash, otherHash) == 1, nil
+}
+
+// Check whether the given Hash was created by an older algorithm or with outdated parameters
+func ComparePasswordOutdated(givenHash string) bool {
+	parts := strings.Split(givenHash, "$")
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return true
+	}
+	return parts[2] != fmt.Sprintf("v=%d", argon2.Version) ||
+		parts[3] != fmt.Sprintf("m=%d,t=%d,p=%d", PASSWORD_MEMORY, PASSWORD_TIME, PASSWORD_THREADS)
 }
 
 // Compare two strings in constant time to prevent leaking of sensitive info
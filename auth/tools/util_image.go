@@ -53,6 +53,12 @@ func ImagePaths(o ImageOptions, id int64, hash string) []string {
 	return paths
 }
 
+// Return Path for the Original Image which is kept privately for data exports,
+// images uploaded before originals were kept will not have one
+func ImageOriginalPath(o ImageOptions, id int64, hash string) string {
+	return path.Join("originals", o.Folder, strconv.FormatInt(id, 10), hash)
+}
+
 // Helper Function that calls ImageProcessor to handle the given image, it aborts the request
 // with the appropriate API Error in case of failure. You should return early if false is returned.
 func ImageHandler(w http.ResponseWriter, r *http.Request, o ImageOptions, id int64, d []byte) (bool, string) {
@@ -146,5 +152,16 @@ func ImageProcessor(o ImageOptions, id int64, d []byte) (string, error) {
 		}
 	}
 
+	// Keep Original Image
+	originalPath := ImageOriginalPath(o, id, imageHash)
+	output, err := StoragePrivateCreate(path.Dir(originalPath), path.Base(originalPath))
+	if err != nil {
+		return imageHash, err
+	}
+	defer output.Close()
+	if _, err := output.Write(d); err != nil {
+		return imageHash, err
+	}
+
 	return imageHash, nil
 }
@@ -0,0 +1,106 @@
+package tools
+
+import (
+	"net/http"
+	"strings"
+)
+
+const (
+	MFA_METHOD_PASSCODE = "passcode"      // Passcode from an Authenticator App
+	MFA_METHOD_RECOVERY = "recovery_code" // Single Use Recovery Code
+)
+
+// Verify a Passcode from the Authenticator App or a Recovery Code of the given User, a valid recovery code
+// is marked as used and any pending MFA removal is cancelled. On failure an error is sent to the client and
+// false is returned, the method attempted is returned either way so that failures can be recorded (it is
+// empty if no passcode was given at all)
+func ValidateMFA(w http.ResponseWriter, r *http.Request, userID int64, mfaSecret, mfaCodesRAW string, mfaCodesUsed int, passcode string) (bool, string) {
+	switch len(passcode) {
+
+	// Missing Passcode
+	case 0:
+		SendClientError(w, r, ERROR_MFA_PASSCODE_REQUIRED)
+		return false, ""
+
+	// Using Passcode
+	case MFA_PASSCODE_LENGTH:
+		if !ValidateTOTPCode(passcode, mfaSecret) {
+			SendClientError(w, r, ERROR_MFA_PASSCODE_INCORRECT)
+			return false, MFA_METHOD_PASSCODE
+		}
+		if !cancelMFARemoval(w, r, userID) {
+			return false, MFA_METHOD_PASSCODE
+		}
+		return true, MFA_METHOD_PASSCODE
+
+	// Using Recovery Code
+	case MFA_RECOVERY_LENGTH:
+		for i, recoveryCode := range strings.Split(mfaCodesRAW, ARRAY_DELIMITER) {
+			if !CompareStringConstant(passcode, recoveryCode) {
+				continue
+			}
+
+			// Code Used?
+			// 	Checked again while marking to prevent the same code being used by concurrent requests
+			if (mfaCodesUsed & (1 << i)) != 0 {
+				SendClientError(w, r, ERROR_MFA_RECOVERY_CODE_USED)
+				return false, MFA_METHOD_RECOVERY
+			}
+
+			// Mark Recovery Code as Used
+			tag, err := Database.ExecContext(r.Context(),
+				"UPDATE user SET mfa_codes_used = mfa_codes_used | ? WHERE id = ? AND mfa_codes_used & ? = 0",
+				(1 << i),
+				userID,
+				(1 << i),
+			)
+			if err != nil {
+				SendServerError(w, r, err)
+				return false, MFA_METHOD_RECOVERY
+			}
+			if c, err := tag.RowsAffected(); err != nil {
+				SendServerError(w, r, err)
+				return false, MFA_METHOD_RECOVERY
+			} else if c == 0 {
+				SendClientError(w, r, ERROR_MFA_RECOVERY_CODE_USED)
+				return false, MFA_METHOD_RECOVERY
+			}
+			RecordUserEvent(r, userID, EVENT_MFA_RECOVERY_USED, "")
+			if !cancelMFARemoval(w, r, userID) {
+				return false, MFA_METHOD_RECOVERY
+			}
+			return true, MFA_METHOD_RECOVERY
+		}
+		SendClientError(w, r, ERROR_MFA_RECOVERY_CODE_INCORRECT)
+		return false, MFA_METHOD_RECOVERY
+
+	default:
+		// Should have been caught by the validator!
+		SendClientError(w, r, ERROR_MFA_PASSCODE_INCORRECT)
+		return false, MFA_METHOD_PASSCODE
+	}
+}
+
+// Cancel a pending MFA Removal of the given User, the removal is requested through the email
+// address which may be compromised so proving possession of a second factor cancels it as well
+func cancelMFARemoval(w http.ResponseWriter, r *http.Request, userID int64) bool {
+	tag, err := Database.ExecContext(r.Context(),
+		`UPDATE user SET
+			updated 		  = CURRENT_TIMESTAMP,
+			mfa_removal_at 	  = NULL,
+			token_mfa_removal = NULL
+		WHERE id = ? AND mfa_removal_at IS NOT NULL`,
+		userID,
+	)
+	if err != nil {
+		SendServerError(w, r, err)
+		return false
+	}
+	if c, err := tag.RowsAffected(); err != nil {
+		SendServerError(w, r, err)
+		return false
+	} else if c > 0 {
+		RecordUserEvent(r, userID, EVENT_MFA_REMOVAL_CANCELLED, "")
+	}
+	return true
+}
@@ -25,3 +25,14 @@ func (mh MethodHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		SendClientError(w, r, ERROR_GENERIC_METHOD_NOT_ALLOWED)
 	}
 }
+
+type ResourceHandler map[string]http.Handler
+
+// Route Sub-Resources sharing a pattern by the value of the '{resource}' wildcard
+func (rh ResourceHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if handler, ok := rh[r.PathValue("resource")]; ok {
+		handler.ServeHTTP(w, r)
+	} else {
+		SendClientError(w, r, ERROR_GENERIC_NOT_FOUND)
+	}
+}
@@ -0,0 +1,135 @@
+package tools
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Public Profile of a User, visible to anyone
+type UserProfile struct {
+	ID               int64     `json:"id"`
+	Created          time.Time `json:"created"`
+	Username         string    `json:"username"`
+	Displayname      string    `json:"displayname"`
+	Subtitle         *string   `json:"subtitle"`
+	Biography        *string   `json:"biography"`
+	AvatarHash       *string   `json:"avatar"`
+	BannerHash       *string   `json:"banner"`
+	AccentBanner     *int      `json:"accent_banner"`
+	AccentBorder     *int      `json:"accent_border"`
+	AccentBackground *int      `json:"accent_background"`
+	Revision         int64     `json:"revision"`
+}
+
+// Fetch the Profiles of every User matching the given condition, accounts pending deletion are excluded
+func ProfileQuery(ctx context.Context, condition string, args ...any) ([]UserProfile, error) {
+	rows, err := Database.QueryContext(ctx,
+		`SELECT
+			id, created, username, displayname,
+			subtitle, biography, avatar_hash, banner_hash,
+			accent_banner, accent_border, accent_background, revision
+		FROM user WHERE (`+condition+`) AND deleted_at IS NULL`,
+		args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	profiles := make([]UserProfile, 0, 1)
+	for rows.Next() {
+		var p UserProfile
+		if err := rows.Scan(
+			&p.ID,
+			&p.Created,
+			&p.Username,
+			&p.Displayname,
+			&p.Subtitle,
+			&p.Biography,
+			&p.AvatarHash,
+			&p.BannerHash,
+			&p.AccentBanner,
+			&p.AccentBorder,
+			&p.AccentBackground,
+			&p.Revision,
+		); err != nil {
+			return nil, err
+		}
+		profiles = append(profiles, p)
+	}
+	return profiles, rows.Err()
+}
+
+// Fetch the Profiles of the given Users in the order they were given,
+// IDs which do not belong to any visible user are returned separately
+func ProfileQueryBulk(ctx context.Context, userIDs []int64) ([]UserProfile, []int64, error) {
+	placeholders := make([]string, len(userIDs))
+	arguments := make([]any, len(userIDs))
+	for i, id := range userIDs {
+		placeholders[i] = "?"
+		arguments[i] = id
+	}
+	profiles, err := ProfileQuery(ctx, "id IN ("+strings.Join(placeholders, ",")+")", arguments...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Organize Results
+	found := make(map[int64]UserProfile, len(profiles))
+	for _, p := range profiles {
+		found[p.ID] = p
+	}
+	var (
+		ordered = make([]UserProfile, 0, len(profiles))
+		unknown = make([]int64, 0)
+		seen    = make(map[int64]bool, len(userIDs))
+	)
+	for _, id := range userIDs {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		if p, ok := found[id]; ok {
+			ordered = append(ordered, p)
+		} else {
+			unknown = append(unknown, id)
+		}
+	}
+	return ordered, unknown, nil
+}
+
+// Generate ETag for the given Profile
+func ProfileHash(p UserProfile) string {
+	b, _ := json.Marshal(p)
+	return fmt.Sprintf("%x", sha256.Sum256(b))
+}
+
+// Send the given Profile, replying with 304 Not Modified if the client already has it cached
+func SendProfile(w http.ResponseWriter, r *http.Request, p UserProfile) {
+	storedTag := `"` + ProfileHash(p) + `"`
+	w.Header().Set("ETag", storedTag)
+	if etagNoneMatch(r.Header.Values("If-None-Match"), storedTag) {
+		SendJSON(w, r, http.StatusOK, p)
+		return
+	}
+	w.WriteHeader(http.StatusNotModified)
+}
+
+// Evaluate If-None-Match against the given ETag using weak comparison (RFC 9110 13.1.2),
+// returns true if the representation should be sent
+func etagNoneMatch(headers []string, etag string) bool {
+	for _, h := range headers {
+		for _, given := range strings.Split(h, ",") {
+			given = strings.TrimSpace(given)
+			if given == "*" || strings.TrimPrefix(given, "W/") == strings.TrimPrefix(etag, "W/") {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,282 @@
+package tools
+
+import (
+	"cmp"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Settings are stored as an opaque blob at `{DATA_DIRECTORY}/settings/{id}.raw`, previous
+// versions are kept in `{id}.history/{version}.raw` where the version is the time it was replaced.
+// Writes are serialized per user so that If-Match and quota checks cannot race each other.
+//
+// Named namespaces are stored alongside as `{id}.{namespace}.raw` and `{id}.{namespace}.history`,
+// the empty namespace refers to the original blob. Per-session namespaces are named `@{session_id}`
+// which cannot collide with user given names, and are removed once their session no longer exists.
+
+type SettingsNamespace struct {
+	Name    string    `json:"name"`
+	Updated time.Time `json:"updated"`
+	Size    int64     `json:"size"`
+	ETag    string    `json:"etag"`
+}
+
+type SettingsVersion struct {
+	Version  int64     `json:"version"`
+	Replaced time.Time `json:"replaced"`
+	Size     int64     `json:"size"`
+	ETag     string    `json:"etag"`
+}
+
+var (
+	ErrSettingsPreconditionFailed = errors.New("settings precondition failed")
+	ErrSettingsNamespaceLimit     = errors.New("settings namespace limit reached")
+	settingsLocks                 [64]sync.Mutex
+)
+
+// Return Path for the current Settings of the given User and Namespace
+func SettingsPath(userID int64, namespace string) string {
+	return settingsPrefix(userID, namespace) + ".raw"
+}
+
+// Return Path for the Settings History of the given User and Namespace
+func SettingsHistoryPath(userID int64, namespace string) string {
+	return settingsPrefix(userID, namespace) + ".history"
+}
+
+func settingsPrefix(userID int64, namespace string) string {
+	filename := strconv.FormatInt(userID, 10)
+	if namespace != "" {
+		filename += "." + namespace
+	}
+	return path.Join(DATA_DIRECTORY, "settings", filename)
+}
+
+// Generate ETag for the given Settings
+func SettingsHash(data []byte) string {
+	return fmt.Sprintf("%x", sha256.Sum256(data))
+}
+
+// Read the current Settings of the given User
+func SettingsRead(userID int64, namespace string) ([]byte, error) {
+	data, err := os.ReadFile(SettingsPath(userID, namespace))
+	if os.IsNotExist(err) {
+		return nil, ErrStorageFileNotFound
+	}
+	return data, err
+}
+
+// Replace the current Settings of the given User, the previous settings are moved into their history.
+// If ifMatch is not empty it must match the ETag of the current settings or '*' if any must exist,
+// creating a named namespace fails with ErrSettingsNamespaceLimit once the user has too many of them
+func SettingsWrite(userID int64, namespace string, data []byte, ifMatch string) (string, error) {
+	lock := &settingsLocks[userID%int64(len(settingsLocks))]
+	lock.Lock()
+	defer lock.Unlock()
+
+	// Check Precondition
+	current, err := SettingsRead(userID, namespace)
+	if err != nil && err != ErrStorageFileNotFound {
+		return "", err
+	}
+	exists := err == nil
+	if ifMatch != "" {
+		if !exists || (ifMatch != "*" && ifMatch != SettingsHash(current)) {
+			return "", ErrSettingsPreconditionFailed
+		}
+	}
+
+	// Check Namespace Limit
+	// 	Every namespace has its own quota, so the amount of them must be limited
+	if !exists && namespace != "" && !strings.HasPrefix(namespace, "@") {
+		namespaces, err := SettingsList(userID)
+		if err != nil {
+			return "", err
+		}
+		named := 0
+		for _, ns := range namespaces {
+			if ns.Name != "" && !strings.HasPrefix(ns.Name, "@") {
+				named++
+			}
+		}
+		if named >= SETTINGS_NAMESPACE_LIMIT {
+			return "", ErrSettingsNamespaceLimit
+		}
+	}
+
+	// Write Temporary File
+	// 	Renaming is atomic so readers will never see a partially written file
+	currentPath := SettingsPath(userID, namespace)
+	temp, err := os.CreateTemp(path.Dir(currentPath), ".tmp-*")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(temp.Name())
+	if _, err := temp.Write(data); err != nil {
+		temp.Close()
+		return "", err
+	}
+	if err := temp.Sync(); err != nil {
+		temp.Close()
+		return "", err
+	}
+	if err := temp.Close(); err != nil {
+		return "", err
+	}
+	if err := os.Chmod(temp.Name(), FILEMODE_SECURE); err != nil {
+		return "", err
+	}
+
+	// Archive Previous Settings
+	// 	A hard link keeps the current file in place until it is replaced below
+	if exists {
+		historyPath := SettingsHistoryPath(userID, namespace)
+		if err := os.MkdirAll(historyPath, FILEMODE_SECURE); err != nil {
+			return "", err
+		}
+		version := strconv.FormatInt(time.Now().UnixNano(), 10) + ".raw"
+		if err := os.Link(currentPath, path.Join(historyPath, version)); err != nil {
+			return "", err
+		}
+		if err := settingsPrune(userID, namespace); err != nil {
+			LoggerStorage.Data(ERROR, "Failed to Prune Settings History", map[string]any{
+				"user_id":   userID,
+				"namespace": namespace,
+				"error":     err.Error(),
+			})
+		}
+	}
+
+	// Replace Current Settings
+	if err := os.Rename(temp.Name(), currentPath); err != nil {
+		return "", err
+	}
+	return SettingsHash(data), nil
+}
+
+// List previous Settings of the given User, newest first
+func SettingsHistory(userID int64, namespace string) ([]SettingsVersion, error) {
+	entries, err := os.ReadDir(SettingsHistoryPath(userID, namespace))
+	if os.IsNotExist(err) {
+		return []SettingsVersion{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	versions := make([]SettingsVersion, 0, len(entries))
+	for _, ent := range entries {
+		version, err := strconv.ParseInt(strings.TrimSuffix(ent.Name(), ".raw"), 10, 64)
+		if err != nil || !ent.Type().IsRegular() {
+			continue
+		}
+		data, err := SettingsReadVersion(userID, namespace, version)
+		if err != nil {
+			return nil, err
+		}
+		versions = append(versions, SettingsVersion{
+			Version:  version,
+			Replaced: time.Unix(0, version),
+			Size:     int64(len(data)),
+			ETag:     SettingsHash(data),
+		})
+	}
+	slices.SortFunc(versions, func(a, b SettingsVersion) int {
+		return cmp.Compare(b.Version, a.Version)
+	})
+	return versions, nil
+}
+
+// Read a previous version of the Settings of the given User
+func SettingsReadVersion(userID int64, namespace string, version int64) ([]byte, error) {
+	data, err := os.ReadFile(path.Join(SettingsHistoryPath(userID, namespace), strconv.FormatInt(version, 10)+".raw"))
+	if os.IsNotExist(err) {
+		return nil, ErrStorageFileNotFound
+	}
+	return data, err
+}
+
+// Delete the current Settings and History of the given User and Namespace
+func SettingsDelete(userID int64, namespace string) error {
+	lock := &settingsLocks[userID%int64(len(settingsLocks))]
+	lock.Lock()
+	defer lock.Unlock()
+
+	if err := os.Remove(SettingsPath(userID, namespace)); err != nil {
+		if os.IsNotExist(err) {
+			return ErrStorageFileNotFound
+		}
+		return err
+	}
+	return os.RemoveAll(SettingsHistoryPath(userID, namespace))
+}
+
+// Delete every Namespace, Settings and History of the given User
+func SettingsDeleteAll(userID int64) error {
+	matches, err := filepath.Glob(path.Join(DATA_DIRECTORY, "settings", strconv.FormatInt(userID, 10)+".*"))
+	if err != nil {
+		return err
+	}
+	for _, match := range matches {
+		if err := os.RemoveAll(match); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// List the Namespaces of the given User, the empty namespace is included if it exists
+func SettingsList(userID int64) ([]SettingsNamespace, error) {
+	prefix := strconv.FormatInt(userID, 10) + "."
+	matches, err := filepath.Glob(path.Join(DATA_DIRECTORY, "settings", prefix+"*raw"))
+	if err != nil {
+		return nil, err
+	}
+
+	namespaces := make([]SettingsNamespace, 0, len(matches))
+	for _, match := range matches {
+		name := strings.TrimSuffix(strings.TrimPrefix(path.Base(match), prefix), "raw")
+		name = strings.TrimSuffix(name, ".")
+		data, err := SettingsRead(userID, name)
+		if err == ErrStorageFileNotFound {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		info, err := os.Stat(match)
+		if err != nil {
+			return nil, err
+		}
+		namespaces = append(namespaces, SettingsNamespace{
+			Name:    name,
+			Updated: info.ModTime(),
+			Size:    int64(len(data)),
+			ETag:    SettingsHash(data),
+		})
+	}
+	return namespaces, nil
+}
+
+// Remove the oldest versions beyond the history limit, expects the user lock to be held
+func settingsPrune(userID int64, namespace string) error {
+	versions, err := SettingsHistory(userID, namespace)
+	if err != nil {
+		return err
+	}
+	for _, v := range versions[min(len(versions), SETTINGS_HISTORY_LIMIT):] {
+		if err := os.Remove(path.Join(SettingsHistoryPath(userID, namespace), strconv.FormatInt(v.Version, 10)+".raw")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
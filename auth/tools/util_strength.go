@@ -0,0 +1,564 @@
+package tools
+
+import (
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"dsoob/backend/include"
+)
+
+// Password Strength is estimated similarly to zxcvbn, the password is broken down into the sequence of patterns
+// an attacker would most likely try (common passwords and words, details of the user, keyboard patterns, repeats,
+// sequences and years) and the amount of guesses needed to find it is calculated from that sequence. Guesses are
+// kept as base 10 logarithms as they easily overflow otherwise. Long random passwords and passphrases score well
+// regardless of which characters they use while short or predictable ones never do.
+
+type PasswordStrength struct {
+	Score       int      `json:"score"`       // Strength from 0 (Too Guessable) to 4 (Very Unguessable)
+	Warning     string   `json:"warning"`     // Explanation of the Weakest Part, if any
+	Suggestions []string `json:"suggestions"` // Hints for a Stronger Password
+}
+
+type strengthMatch struct {
+	Pattern   string  // Pattern Name
+	Start     int     // Index of First Rune
+	End       int     // Index after Last Rune
+	Guesses   float64 // Guesses Needed (log10)
+	Rank      int     // Dictionary Rank
+	UserInput bool    // Dictionary Match is from the User's details
+	Reversed  bool    // Dictionary Match is Reversed
+	L33t      bool    // Dictionary Match uses Substitutions
+	Turns     int     // Spatial Direction Changes
+	BaseSize  int     // Repeated Base Length
+}
+
+var (
+	strengthDictionary = map[string]int{}
+	strengthWordLength = 0
+	strengthKeyboard   = map[rune][3]float64{} // Row, Column and Shifted
+	strengthL33t       = map[rune]rune{
+		'4': 'a', '@': 'a', '8': 'b', '(': 'c', '{': 'c', '3': 'e', '6': 'g', '9': 'g', '1': 'i',
+		'!': 'i', '|': 'i', '0': 'o', '$': 's', '5': 's', '7': 't', '+': 't', '2': 'z', '%': 'x',
+	}
+)
+
+func init() {
+
+	// Dictionary is Ordered from Most to Least Common
+	for i, word := range strings.Fields(include.PasswordDictionary) {
+		if _, ok := strengthDictionary[word]; !ok {
+			strengthDictionary[word] = i + 1
+			strengthWordLength = max(strengthWordLength, len([]rune(word)))
+		}
+	}
+
+	// QWERTY Layout, rows are offset from each other the same way as on a real keyboard
+	for row, layout := range []struct {
+		Keys    string
+		Shifted string
+		Offset  float64
+	}{
+		{"`1234567890-=", "~!@#$%^&*()_+", 0},
+		{"qwertyuiop[]\\", "QWERTYUIOP{}|", 1.5},
+		{"asdfghjkl;'", "ASDFGHJKL:\"", 1.75},
+		{"zxcvbnm,./", "ZXCVBNM<>?", 2.25},
+	} {
+		shifted := []rune(layout.Shifted)
+		for i, key := range []rune(layout.Keys) {
+			column := layout.Offset + float64(i)
+			strengthKeyboard[key] = [3]float64{float64(row), column, 0}
+			strengthKeyboard[shifted[i]] = [3]float64{float64(row), column, 1}
+		}
+	}
+}
+
+// Estimate the Strength of the given Password, details about the user such
+// as their username and email address are treated as very common words
+func EstimatePasswordStrength(givenPassword string, userInputs ...string) PasswordStrength {
+	password := []rune(givenPassword)
+	if len(password) > PASSWORD_STRENGTH_LIMIT {
+		password = password[:PASSWORD_STRENGTH_LIMIT]
+	}
+
+	// Rank User Details
+	// 	Each detail is also split into its parts, catching names within email addresses
+	inputs := map[string]int{}
+	for _, input := range userInputs {
+		input = strings.ToLower(input)
+		parts := strings.FieldsFunc(input, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+		})
+		for _, part := range append([]string{input}, parts...) {
+			if _, ok := inputs[part]; !ok && len([]rune(part)) >= 3 {
+				inputs[part] = len(inputs) + 1
+			}
+		}
+	}
+
+	guesses, sequence := strengthGuesses(password, inputs)
+	var score int
+	switch {
+	case guesses < math.Log10(1e3+5):
+		score = 0
+	case guesses < math.Log10(1e6+5):
+		score = 1
+	case guesses < math.Log10(1e8+5):
+		score = 2
+	case guesses < math.Log10(1e10+5):
+		score = 3
+	default:
+		score = 4
+	}
+	warning, suggestions := strengthFeedback(score, password, sequence)
+	return PasswordStrength{
+		Score:       score,
+		Warning:     warning,
+		Suggestions: suggestions,
+	}
+}
+
+// Ensure the given Password meets PASSWORD_MIN_SCORE, otherwise the estimate is sent to the client
+func ValidatePasswordStrength(w http.ResponseWriter, r *http.Request, givenPassword string, userInputs ...string) bool {
+	strength := EstimatePasswordStrength(givenPassword, userInputs...)
+	if strength.Score >= PASSWORD_MIN_SCORE {
+		return true
+	}
+	SendJSON(w, r, ERROR_LOGIN_PASSWORD_WEAK.Status, map[string]any{
+		"code":        ERROR_LOGIN_PASSWORD_WEAK.Code,
+		"message":     ERROR_LOGIN_PASSWORD_WEAK.Message,
+		"score":       strength.Score,
+		"warning":     strength.Warning,
+		"suggestions": strength.Suggestions,
+	})
+	return false
+}
+
+// Find the Sequence of Matches needing the fewest guesses to cover the entire password.
+// Every additional match multiplies the guesses by the amount of matches to account for their ordering
+func strengthGuesses(password []rune, inputs map[string]int) (float64, []strengthMatch) {
+	n := len(password)
+	if n == 0 {
+		return 0, nil
+	}
+	matchesByEnd := make([][]strengthMatch, n)
+	for _, m := range strengthMatches(password, inputs) {
+		matchesByEnd[m.End-1] = append(matchesByEnd[m.End-1], m)
+	}
+
+	// optimal[j][k] covers the first j+1 runes using exactly k matches
+	type step struct {
+		Guesses float64
+		Match   strengthMatch
+		Valid   bool
+	}
+	optimal := make([][]step, n)
+	for j := range optimal {
+		optimal[j] = make([]step, n+1)
+		candidates := matchesByEnd[j]
+		for i := 0; i <= j; i++ {
+			candidates = append(candidates, strengthMatch{
+				Pattern: "bruteforce",
+				Start:   i,
+				End:     j + 1,
+				Guesses: strengthMinimum(float64(j+1-i), j+1-i),
+			})
+		}
+		for _, m := range candidates {
+			if m.Start == 0 {
+				if !optimal[j][1].Valid || m.Guesses < optimal[j][1].Guesses {
+					optimal[j][1] = step{m.Guesses, m, true}
+				}
+				continue
+			}
+			for k, previous := range optimal[m.Start-1] {
+				if !previous.Valid {
+					continue
+				}
+				if g := previous.Guesses + m.Guesses; !optimal[j][k+1].Valid || g < optimal[j][k+1].Guesses {
+					optimal[j][k+1] = step{g, m, true}
+				}
+			}
+		}
+	}
+
+	// Pick Best Length and Unwind Sequence
+	var (
+		bestGuesses = math.Inf(1)
+		bestLength  = 0
+	)
+	for k, s := range optimal[n-1] {
+		if !s.Valid {
+			continue
+		}
+		if g := s.Guesses + strengthFactorial(k); g < bestGuesses {
+			bestGuesses, bestLength = g, k
+		}
+	}
+	sequence := make([]strengthMatch, bestLength)
+	for j, k := n-1, bestLength; k > 0; k-- {
+		sequence[k-1] = optimal[j][k].Match
+		j = optimal[j][k].Match.Start - 1
+	}
+	return bestGuesses, sequence
+}
+
+// Find every Pattern within the given Password
+func strengthMatches(password []rune, inputs map[string]int) []strengthMatch {
+	var (
+		n       = len(password)
+		lower   = make([]rune, n)
+		matches = []strengthMatch{}
+	)
+	for i, r := range password {
+		lower[i] = unicode.ToLower(r)
+	}
+
+	// Pattern: Dictionary
+	// 	Words are also checked after undoing common substitutions and when reversed
+	unl33t := make([]rune, n)
+	for i, r := range lower {
+		if s, ok := strengthL33t[r]; ok {
+			unl33t[i] = s
+		} else {
+			unl33t[i] = r
+		}
+	}
+	reversed := slices.Clone(lower)
+	slices.Reverse(reversed)
+	wordLength := strengthWordLength
+	for input := range inputs {
+		wordLength = max(wordLength, len([]rune(input)))
+	}
+	for i := 0; i < n; i++ {
+		for j := i + 3; j <= n && j-i <= wordLength; j++ {
+			token := password[i:j]
+			for _, candidate := range []struct {
+				Word     string
+				L33t     bool
+				Reversed bool
+			}{
+				{string(lower[i:j]), false, false},
+				{string(unl33t[i:j]), true, false},
+				{string(reversed[n-j : n-i]), false, true},
+			} {
+				if candidate.L33t && candidate.Word == string(lower[i:j]) {
+					continue
+				}
+				rank, userInput := inputs[candidate.Word], true
+				if rank == 0 {
+					rank, userInput = strengthDictionary[candidate.Word], false
+				}
+				if rank == 0 {
+					continue
+				}
+				guesses := math.Log10(float64(rank)) + strengthUppercase(token)
+				if candidate.L33t {
+					guesses += strengthSubstitutions(lower[i:j], unl33t[i:j])
+				}
+				if candidate.Reversed {
+					guesses += math.Log10(2)
+				}
+				matches = append(matches, strengthMatch{
+					Pattern:   "dictionary",
+					Start:     i,
+					End:       j,
+					Guesses:   strengthMinimum(guesses, j-i),
+					Rank:      rank,
+					UserInput: userInput,
+					Reversed:  candidate.Reversed,
+					L33t:      candidate.L33t,
+				})
+			}
+		}
+	}
+
+	// Pattern: Spatial
+	// 	Runs of neighbouring keys, each change of direction makes them harder to guess
+	for i := 0; i < n-2; {
+		j, turns, shifted, direction := i+1, 0, 0, [2]float64{}
+		if strengthKeyboard[password[i]][2] == 1 {
+			shifted++
+		}
+		for ; j < n; j++ {
+			a, okA := strengthKeyboard[password[j-1]]
+			b, okB := strengthKeyboard[password[j]]
+			dr, dc := b[0]-a[0], b[1]-a[1]
+			if !okA || !okB || !((dr == 0 && math.Abs(dc) == 1) || (math.Abs(dr) == 1 && math.Abs(dc) <= 1)) {
+				break
+			}
+			if d := [2]float64{dr, math.Copysign(1, dc)}; d != direction {
+				direction = d
+				turns++
+			}
+			if b[2] == 1 {
+				shifted++
+			}
+		}
+		if j-i < 3 {
+			i++
+			continue
+		}
+		length := j - i
+		guesses := 0.0
+		for l := 2; l <= length; l++ {
+			for t := 1; t <= min(turns, l-1); t++ {
+				guesses += strengthBinomial(l-1, t-1) * 94 * math.Pow(4.6, float64(t))
+			}
+		}
+		guesses = math.Log10(guesses)
+		if shifted > 0 {
+			guesses += strengthVariations(shifted, length-shifted)
+		}
+		matches = append(matches, strengthMatch{
+			Pattern: "spatial",
+			Start:   i,
+			End:     j,
+			Guesses: strengthMinimum(guesses, length),
+			Turns:   turns,
+		})
+		i = j
+	}
+
+	// Pattern: Repeat
+	// 	The base is estimated on its own and then multiplied by the amount of repeats
+	for i := 0; i < n-1; {
+		bestSize, bestCount := 0, 0
+		for size := 1; i+size*2 <= n; size++ {
+			count := 1
+			for i+(count+1)*size <= n && slices.Equal(lower[i:i+size], lower[i+count*size:i+(count+1)*size]) {
+				count++
+			}
+			if count >= 2 && size*count > bestSize*bestCount {
+				bestSize, bestCount = size, count
+			}
+		}
+		if bestCount < 2 || bestSize*bestCount < 3 {
+			i++
+			continue
+		}
+		baseGuesses, _ := strengthGuesses(password[i:i+bestSize], inputs)
+		matches = append(matches, strengthMatch{
+			Pattern:  "repeat",
+			Start:    i,
+			End:      i + bestSize*bestCount,
+			Guesses:  strengthMinimum(baseGuesses+math.Log10(float64(bestCount)), bestSize*bestCount),
+			BaseSize: bestSize,
+		})
+		i += bestSize * bestCount
+	}
+
+	// Pattern: Sequence
+	// 	Runs of characters separated by the same small step, such as abc, 2468 or 9876
+	for i := 0; i < n-2; {
+		delta := lower[i+1] - lower[i]
+		j := i + 2
+		for j < n && lower[j]-lower[j-1] == delta {
+			j++
+		}
+		if delta == 0 || delta > 5 || delta < -5 || j-i < 3 {
+			i++
+			continue
+		}
+		var base float64
+		switch first := lower[i]; {
+		case strings.ContainsRune("az019", first):
+			base = 4
+		case unicode.IsDigit(first):
+			base = 10
+		default:
+			base = 26
+		}
+		if delta < 0 {
+			base *= 2
+		}
+		matches = append(matches, strengthMatch{
+			Pattern: "sequence",
+			Start:   i,
+			End:     j,
+			Guesses: strengthMinimum(math.Log10(base*float64(j-i)), j-i),
+		})
+		i = j - 1
+	}
+
+	// Pattern: Year
+	for i := 0; i+4 <= n; i++ {
+		year, err := strconv.Atoi(string(password[i : i+4]))
+		if err != nil || year < 1900 || year > 2099 {
+			continue
+		}
+		matches = append(matches, strengthMatch{
+			Pattern: "year",
+			Start:   i,
+			End:     i + 4,
+			Guesses: math.Log10(math.Max(math.Abs(float64(year-time.Now().Year())), 20)),
+		})
+	}
+
+	return matches
+}
+
+// Describe the weakest part of the Password along with Suggestions to improve it
+func strengthFeedback(score int, password []rune, sequence []strengthMatch) (string, []string) {
+	suggestions := []string{}
+	if score >= 4 {
+		return "", suggestions
+	}
+	suggestions = append(suggestions, "Add another word or two, uncommon words are better")
+
+	// Find Longest Pattern
+	var longest *strengthMatch
+	for i, m := range sequence {
+		if m.Pattern != "bruteforce" && (longest == nil || m.End-m.Start > longest.End-longest.Start) {
+			longest = &sequence[i]
+		}
+	}
+	if longest == nil {
+		return "", append(suggestions, "Use a few words and avoid common phrases")
+	}
+
+	switch longest.Pattern {
+	case "dictionary":
+		token := password[longest.Start:longest.End]
+		var warning string
+		switch {
+		case longest.UserInput:
+			warning = "Avoid using your username or email address"
+		case longest.L33t || longest.Reversed:
+			warning = "This is similar to a commonly used password"
+		case longest.Rank <= 10:
+			warning = "This is a top-10 common password"
+		case longest.Rank <= 100:
+			warning = "This is a top-100 common password"
+		case len(sequence) == 1:
+			warning = "A word by itself is easy to guess"
+		}
+		if unicode.IsUpper(token[0]) && strings.ToLower(string(token[1:])) == string(token[1:]) {
+			suggestions = append(suggestions, "Capitalization doesn't help very much")
+		} else if strings.ToUpper(string(token)) == string(token) && strings.ToLower(string(token)) != string(token) {
+			suggestions = append(suggestions, "All-uppercase is almost as easy to guess as all-lowercase")
+		}
+		if longest.Reversed {
+			suggestions = append(suggestions, "Reversed words aren't much harder to guess")
+		}
+		if longest.L33t {
+			suggestions = append(suggestions, "Predictable substitutions like '@' instead of 'a' don't help very much")
+		}
+		return warning, suggestions
+
+	case "spatial":
+		warning := "Short keyboard patterns are easy to guess"
+		if longest.Turns == 1 {
+			warning = "Straight rows of keys are easy to guess"
+		}
+		return warning, append(suggestions, "Use a longer keyboard pattern with more turns")
+
+	case "repeat":
+		warning := `Repeats like "abcabcabc" are only slightly harder to guess than "abc"`
+		if longest.BaseSize == 1 {
+			warning = `Repeats like "aaa" are easy to guess`
+		}
+		return warning, append(suggestions, "Avoid repeated words and characters")
+
+	case "sequence":
+		return "Sequences like abc or 6543 are easy to guess", append(suggestions, "Avoid sequences")
+
+	case "year":
+		return "Recent years are easy to guess", append(suggestions, "Avoid recent years and years that are associated with you")
+	}
+	return "", suggestions
+}
+
+// Additional Guesses (log10) needed for the capitalization of the given token
+func strengthUppercase(token []rune) float64 {
+	var upper, lower int
+	for _, r := range token {
+		if unicode.IsUpper(r) {
+			upper++
+		} else if unicode.IsLower(r) {
+			lower++
+		}
+	}
+	if upper == 0 {
+		return 0
+	}
+	if lower == 0 || (upper == 1 && (unicode.IsUpper(token[0]) || unicode.IsUpper(token[len(token)-1]))) {
+		return math.Log10(2)
+	}
+	return strengthVariations(upper, lower)
+}
+
+// Additional Guesses (log10) needed for the substitutions within the given token
+func strengthSubstitutions(original, substituted []rune) float64 {
+	guesses := 0.0
+	for letter := range strengthCharacters(substituted) {
+		var subbed, unsubbed int
+		for i, r := range original {
+			if substituted[i] != letter {
+				continue
+			}
+			if r == letter {
+				unsubbed++
+			} else {
+				subbed++
+			}
+		}
+		if subbed > 0 {
+			guesses += strengthVariations(subbed, unsubbed)
+		}
+	}
+	return guesses
+}
+
+// Every distinct character within the given runes
+func strengthCharacters(runes []rune) map[rune]bool {
+	characters := make(map[rune]bool, len(runes))
+	for _, r := range runes {
+		characters[r] = true
+	}
+	return characters
+}
+
+// Ways (log10) to choose which characters were modified, at least doubling the guesses
+func strengthVariations(modified, unmodified int) float64 {
+	if modified == 0 || unmodified == 0 {
+		return math.Log10(2)
+	}
+	variations := 0.0
+	for i := 1; i <= min(modified, unmodified); i++ {
+		variations += strengthBinomial(modified+unmodified, i)
+	}
+	return math.Log10(variations)
+}
+
+// Raise Guesses (log10) to the minimum for a match of the given length,
+// preventing short matches from being cheaper than guessing them outright
+func strengthMinimum(guesses float64, length int) float64 {
+	if length == 1 {
+		return math.Max(guesses, math.Log10(11))
+	}
+	return math.Max(guesses, math.Log10(51))
+}
+
+func strengthBinomial(n, k int) float64 {
+	if k < 0 || k > n {
+		return 0
+	}
+	r := 1.0
+	for i := 1; i <= k; i++ {
+		r = r * float64(n-k+i) / float64(i)
+	}
+	return r
+}
+
+func strengthFactorial(n int) float64 {
+	f := 0.0
+	for i := 2; i <= n; i++ {
+		f += math.Log10(float64(i))
+	}
+	return f
+}